@@ -0,0 +1,169 @@
+package httpSwagger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	swaggerFiles "github.com/swaggo/files"
+)
+
+// brotliLevel is the brotli quality the assets are compressed with. With the pure Go encoder the
+// best one takes seconds for the Swagger UI bundle, this one less than 0.1s for the assets
+// of the index page, at the cost of a few percent in size.
+const brotliLevel = 6
+
+// the assets loaded by the index page are compressed with brotli at init, so that no request waits
+// for it. The other ones are compressed when first requested.
+func init() {
+	for _, name := range integrityAssets {
+		assets[name].Brotlied()
+	}
+}
+
+// asset is a static Swagger UI file compiled into the binary.
+type asset struct {
+	content []byte
+
+	once sync.Once
+	etag string
+
+	gzipOnce sync.Once
+	gzipped  []byte
+
+	brotliOnce sync.Once
+	brotlied   []byte
+
+	integrityOnce sync.Once
+	integrity     string
+}
+
+// assets holds the embedded Swagger UI files by name.
+var assets = map[string]*asset{
+	"favicon-16x16.png":                   {content: swaggerFiles.FileFavicon16x16Png},
+	"favicon-32x32.png":                   {content: swaggerFiles.FileFavicon32x32Png},
+	"index.css":                           {content: swaggerFiles.FileIndexCSS},
+	"oauth2-redirect.html":                {content: swaggerFiles.FileOauth2RedirectHTML},
+	"swagger-initializer.js":              {content: swaggerFiles.FileSwaggerInitializerJs},
+	"swagger-ui-bundle.js":                {content: swaggerFiles.FileSwaggerUIBundleJs},
+	"swagger-ui-bundle.js.map":            {content: swaggerFiles.FileSwaggerUIBundleJsMap},
+	"swagger-ui-es-bundle-core.js":        {content: swaggerFiles.FileSwaggerUIEsBundleCoreJs},
+	"swagger-ui-es-bundle-core.js.map":    {content: swaggerFiles.FileSwaggerUIEsBundleCoreJsMap},
+	"swagger-ui-es-bundle.js":             {content: swaggerFiles.FileSwaggerUIEsBundleJs},
+	"swagger-ui-es-bundle.js.map":         {content: swaggerFiles.FileSwaggerUIEsBundleJsMap},
+	"swagger-ui-standalone-preset.js":     {content: swaggerFiles.FileSwaggerUIStandalonePresetJs},
+	"swagger-ui-standalone-preset.js.map": {content: swaggerFiles.FileSwaggerUIStandalonePresetJsMap},
+	"swagger-ui.css":                      {content: swaggerFiles.FileSwaggerUICSS},
+	"swagger-ui.css.map":                  {content: swaggerFiles.FileSwaggerUICSSMap},
+	"swagger-ui.js":                       {content: swaggerFiles.FileSwaggerUIJs},
+	"swagger-ui.js.map":                   {content: swaggerFiles.FileSwaggerUIJsMap},
+}
+
+// ETag returns a strong entity tag computed from the asset content.
+func (a *asset) ETag() string {
+	a.once.Do(func() {
+		sum := sha256.Sum256(a.content)
+		a.etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+	})
+
+	return a.etag
+}
+
+// Integrity returns the subresource integrity metadata of the asset content.
+func (a *asset) Integrity() string {
+	a.integrityOnce.Do(func() {
+		sum := sha512.Sum384(a.content)
+		a.integrity = "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+	})
+
+	return a.integrity
+}
+
+// Gzipped returns the asset content compressed with gzip. It is compressed once and reused.
+func (a *asset) Gzipped() []byte {
+	a.gzipOnce.Do(func() {
+		var buf bytes.Buffer
+
+		zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		_, _ = zw.Write(a.content)
+		_ = zw.Close()
+
+		a.gzipped = buf.Bytes()
+	})
+
+	return a.gzipped
+}
+
+// Brotlied returns the asset content compressed with brotli. It is compressed once and reused,
+// at init for the assets of the index page.
+func (a *asset) Brotlied() []byte {
+	a.brotliOnce.Do(func() {
+		var buf bytes.Buffer
+
+		zw := brotli.NewWriterLevel(&buf, brotliLevel)
+		_, _ = zw.Write(a.content)
+		_ = zw.Close()
+
+		a.brotlied = buf.Bytes()
+	})
+
+	return a.brotlied
+}
+
+// ServeHTTP serves the asset content, answering conditional requests with 304 Not Modified
+// and compressing the response with brotli or gzip when the client accepts it, preferring brotli.
+func (a *asset) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", http.DetectContentType(a.content))
+	}
+
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	content, etag := a.content, a.ETag()
+	switch {
+	case acceptsEncoding(r, "br"):
+		content, etag = a.Brotlied(), strings.TrimSuffix(etag, `"`)+`-br"`
+		w.Header().Set("Content-Encoding", "br")
+	case acceptsEncoding(r, "gzip"):
+		content, etag = a.Gzipped(), strings.TrimSuffix(etag, `"`)+`-gzip"`
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+
+	w.Header().Set("ETag", etag)
+
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+}
+
+// acceptsEncoding reports whether the request Accept-Encoding header allows the given content coding.
+func acceptsEncoding(r *http.Request, coding string) bool {
+	for _, v := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		params := strings.Split(v, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), coding) {
+			continue
+		}
+
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") {
+				continue
+			}
+
+			if q, err := strconv.ParseFloat(p[2:], 64); err == nil && q == 0 {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
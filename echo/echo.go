@@ -0,0 +1,29 @@
+package echoSwagger
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	httpSwagger "github.com/swaggo/http-swagger"
+)
+
+// WrapHandler wraps httpSwagger.Handler into an echo.HandlerFunc serving Swagger UI and the API
+// definition under a wildcard route, e.g. `e.GET("/swagger/*", echoSwagger.WrapHandler())`.
+// It panics if the configuration is invalid and StrictValidation is set.
+func WrapHandler(configFns ...func(*httpSwagger.Config)) echo.HandlerFunc {
+	h := httpSwagger.NewHandler(configFns...)
+
+	return func(c echo.Context) error {
+		// resolve the file from the wildcard parameter, so that the route prefix is the
+		// directory assets and the spec are served from
+		file := c.Param("*")
+		prefix := strings.TrimSuffix(c.Request().URL.Path, file)
+
+		r := c.Request().Clone(c.Request().Context())
+		r.RequestURI = prefix + file
+
+		h.ServeHTTP(c.Response(), r)
+
+		return nil
+	}
+}
@@ -0,0 +1,37 @@
+package echoSwagger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	httpSwagger "github.com/swaggo/http-swagger"
+)
+
+func TestWrapHandler(t *testing.T) {
+	router := echo.New()
+	router.GET("/api/swagger/*", WrapHandler(httpSwagger.Title("Petstore")))
+
+	w1 := performRequest(http.MethodGet, "/api/swagger/index.html", router)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Contains(t, w1.Body.String(), "<title>Petstore</title>")
+
+	w2 := performRequest(http.MethodGet, "/api/swagger/swagger-ui.css?v=1", router)
+	assert.Equal(t, http.StatusOK, w2.Code)
+	assert.Equal(t, "text/css; charset=utf-8", w2.Header().Get("Content-Type"))
+
+	w3 := performRequest(http.MethodGet, "/api/swagger/", router)
+	assert.Equal(t, http.StatusMovedPermanently, w3.Code)
+	assert.Equal(t, "/api/swagger/index.html", w3.Header().Get("Location"))
+}
+
+func performRequest(method, target string, h http.Handler) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	return w
+}
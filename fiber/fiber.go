@@ -0,0 +1,31 @@
+package fiberSwagger
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	httpSwagger "github.com/swaggo/http-swagger"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+)
+
+// WrapHandler wraps httpSwagger.Handler into a fiber.Handler serving Swagger UI and the API
+// definition under a wildcard route, e.g. `app.Get("/swagger/*", fiberSwagger.WrapHandler())`.
+// It panics if the configuration is invalid and StrictValidation is set.
+func WrapHandler(configFns ...func(*httpSwagger.Config)) fiber.Handler {
+	h := httpSwagger.NewHandler(configFns...)
+
+	return func(c *fiber.Ctx) error {
+		// resolve the file from the wildcard parameter, so that the route prefix is the
+		// directory assets and the spec are served from
+		file := c.Params("*")
+		requestURI := strings.TrimSuffix(c.Path(), file) + file
+
+		fasthttpadaptor.NewFastHTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.RequestURI = requestURI
+			h.ServeHTTP(w, r)
+		}))(c.Context())
+
+		return nil
+	}
+}
@@ -0,0 +1,40 @@
+package fiberSwagger
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	httpSwagger "github.com/swaggo/http-swagger"
+)
+
+func TestWrapHandler(t *testing.T) {
+	app := fiber.New()
+	app.Get("/api/swagger/*", WrapHandler(httpSwagger.Title("Petstore")))
+
+	w1 := performRequest(t, http.MethodGet, "/api/swagger/index.html", app)
+	assert.Equal(t, http.StatusOK, w1.StatusCode)
+	body, err := ioutil.ReadAll(w1.Body)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "<title>Petstore</title>")
+
+	w2 := performRequest(t, http.MethodGet, "/api/swagger/swagger-ui.css?v=1", app)
+	assert.Equal(t, http.StatusOK, w2.StatusCode)
+	assert.Equal(t, "text/css; charset=utf-8", w2.Header.Get("Content-Type"))
+
+	w3 := performRequest(t, http.MethodGet, "/api/swagger/", app)
+	assert.Equal(t, http.StatusMovedPermanently, w3.StatusCode)
+	assert.Equal(t, "/api/swagger/index.html", w3.Header.Get("Location"))
+}
+
+func performRequest(t *testing.T, method, target string, app *fiber.App) *http.Response {
+	w, err := app.Test(httptest.NewRequest(method, target, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return w
+}
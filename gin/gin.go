@@ -0,0 +1,41 @@
+package ginSwagger
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	httpSwagger "github.com/swaggo/http-swagger"
+)
+
+// WrapHandler wraps httpSwagger.Handler into a gin.HandlerFunc serving Swagger UI and the API
+// definition under a catch-all route, e.g. `r.GET("/swagger/*any", ginSwagger.WrapHandler())`.
+// It panics if the configuration is invalid and StrictValidation is set.
+func WrapHandler(configFns ...func(*httpSwagger.Config)) gin.HandlerFunc {
+	h := httpSwagger.NewHandler(configFns...)
+
+	return func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, stripPrefix(c))
+	}
+}
+
+// stripPrefix returns the request with its RequestURI resolved from the catch-all route
+// parameter, so that the route prefix is the directory assets and the spec are served from.
+func stripPrefix(c *gin.Context) *http.Request {
+	if len(c.Params) == 0 {
+		return c.Request
+	}
+
+	// a catch-all parameter is always the last one of the route, and starts with a slash
+	file := c.Params[len(c.Params)-1].Value
+	if !strings.HasPrefix(file, "/") {
+		return c.Request
+	}
+
+	prefix := strings.TrimSuffix(c.Request.URL.Path, file)
+
+	r := c.Request.Clone(c.Request.Context())
+	r.RequestURI = prefix + file
+
+	return r
+}
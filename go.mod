@@ -3,9 +3,11 @@ module github.com/swaggo/http-swagger
 go 1.17
 
 require (
+	github.com/andybalholm/brotli v1.0.4
 	github.com/stretchr/testify v1.7.0
 	github.com/swaggo/files v0.0.0-20220610200504-28940afbdbfe
 	github.com/swaggo/swag v1.8.1
+	gopkg.in/yaml.v2 v2.4.0
 )
 
 require (
@@ -21,6 +23,5 @@ require (
 	golang.org/x/net v0.0.0-20220425223048-2871e0cb64e4 // indirect
 	golang.org/x/sys v0.0.0-20211216021012-1d35b9e2eb4e // indirect
 	golang.org/x/tools v0.1.10 // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20200615113413-eeeca48fe776 // indirect
 )
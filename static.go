@@ -0,0 +1,382 @@
+package httpSwagger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// staticMethods are the operation methods of a path item, in display order.
+var staticMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// staticSpec is the part of an API definition rendered by the static reference page.
+type staticSpec struct {
+	Title       string
+	Version     string
+	Description string
+	Operations  []staticOperation
+	Schemas     []staticSchema
+}
+
+type staticOperation struct {
+	ID          string
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+	Parameters  []staticField
+	Responses   []staticResponse
+}
+
+type staticResponse struct {
+	Code        string
+	Description string
+	Type        string
+}
+
+type staticSchema struct {
+	Name        string
+	Description string
+	Properties  []staticField
+}
+
+// staticField is a parameter or a schema property.
+type staticField struct {
+	Name        string
+	In          string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// renderStatic renders the static reference page of the API definition doc, either JSON or YAML.
+func renderStatic(data TemplateData, doc []byte) ([]byte, error) {
+	spec, err := decodeSpec(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	view := newStaticSpec(spec)
+
+	var buf bytes.Buffer
+	if err := staticTemplate.Execute(&buf, struct {
+		TemplateData
+		Reference staticSpec
+	}{data, view}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeSpec decodes a JSON or YAML API definition into JSON compatible values.
+func decodeSpec(doc []byte) (map[string]interface{}, error) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal(doc, &spec); err == nil {
+		return spec, nil
+	}
+
+	var v interface{}
+	if err := yaml.Unmarshal(doc, &v); err != nil {
+		return nil, fmt.Errorf("httpSwagger: decoding API definition: %w", err)
+	}
+
+	spec, ok := jsonValue(v).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("httpSwagger: decoding API definition: not an object")
+	}
+
+	return spec, nil
+}
+
+// jsonValue converts the maps decoded by yaml.v2 into maps with string keys.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = jsonValue(e)
+		}
+
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = jsonValue(e)
+		}
+
+		return v
+	default:
+		return v
+	}
+}
+
+func newStaticSpec(spec map[string]interface{}) staticSpec {
+	info := object(spec["info"])
+
+	view := staticSpec{
+		Title:       str(info["title"]),
+		Version:     str(info["version"]),
+		Description: str(info["description"]),
+	}
+
+	paths := object(spec["paths"])
+	for _, p := range sortedKeys(paths) {
+		item := object(paths[p])
+
+		for _, method := range staticMethods {
+			op, ok := item[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			view.Operations = append(view.Operations, newStaticOperation(method, p, item, op))
+		}
+	}
+
+	// Swagger 2.0 definitions or OpenAPI 3 component schemas
+	schemas := object(spec["definitions"])
+	if len(schemas) == 0 {
+		schemas = object(object(spec["components"])["schemas"])
+	}
+
+	for _, name := range sortedKeys(schemas) {
+		schema := object(schemas[name])
+		required := strs(schema["required"])
+		properties := object(schema["properties"])
+
+		s := staticSchema{Name: name, Description: str(schema["description"])}
+		for _, prop := range sortedKeys(properties) {
+			s.Properties = append(s.Properties, staticField{
+				Name:        prop,
+				Type:        schemaType(object(properties[prop])),
+				Description: str(object(properties[prop])["description"]),
+				Required:    contains(required, prop),
+			})
+		}
+
+		view.Schemas = append(view.Schemas, s)
+	}
+
+	return view
+}
+
+func newStaticOperation(method, path string, item, op map[string]interface{}) staticOperation {
+	o := staticOperation{
+		ID:          str(op["operationId"]),
+		Method:      strings.ToUpper(method),
+		Path:        path,
+		Summary:     str(op["summary"]),
+		Description: str(op["description"]),
+		Tags:        strs(op["tags"]),
+		Deprecated:  op["deprecated"] == true,
+	}
+
+	if o.ID == "" {
+		o.ID = method + strings.NewReplacer("/", "-", "{", "", "}", "").Replace(path)
+	}
+
+	params, _ := item["parameters"].([]interface{})
+	ops, _ := op["parameters"].([]interface{})
+
+	for _, p := range append(append([]interface{}{}, params...), ops...) {
+		param := object(p)
+
+		// Swagger 2.0 parameters have a type, OpenAPI 3 ones a schema
+		typ := schemaType(param)
+		if schema, ok := param["schema"].(map[string]interface{}); ok {
+			typ = schemaType(schema)
+		}
+
+		o.Parameters = append(o.Parameters, staticField{
+			Name:        str(param["name"]),
+			In:          str(param["in"]),
+			Type:        typ,
+			Description: str(param["description"]),
+			Required:    param["required"] == true,
+		})
+	}
+
+	responses := object(op["responses"])
+	for _, code := range sortedKeys(responses) {
+		resp := object(responses[code])
+
+		typ := schemaType(object(resp["schema"]))
+		for _, content := range object(resp["content"]) {
+			if t := schemaType(object(object(content)["schema"])); t != "" {
+				typ = t
+
+				break
+			}
+		}
+
+		o.Responses = append(o.Responses, staticResponse{Code: code, Description: str(resp["description"]), Type: typ})
+	}
+
+	return o
+}
+
+// schemaType describes the type of a schema, e.g. "string", "[]Pet" or "Pet".
+func schemaType(schema map[string]interface{}) string {
+	if ref := str(schema["$ref"]); ref != "" {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+
+	typ := str(schema["type"])
+	if types := strs(schema["type"]); len(types) > 0 {
+		// OpenAPI 3.1 type arrays
+		typ = strings.Join(types, " | ")
+	}
+
+	if typ == "array" {
+		return "[]" + schemaType(object(schema["items"]))
+	}
+
+	if format := str(schema["format"]); format != "" && typ != "" {
+		return typ + " (" + format + ")"
+	}
+
+	return typ
+}
+
+func object(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+
+	return m
+}
+
+func str(v interface{}) string {
+	s, _ := v.(string)
+
+	return s
+}
+
+func strs(v interface{}) []string {
+	vs, _ := v.([]interface{})
+
+	ss := make([]string, 0, len(vs))
+	for _, v := range vs {
+		if s, ok := v.(string); ok {
+			ss = append(ss, s)
+		}
+	}
+
+	return ss
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+var staticTemplate = template.Must(template.New("static_index.html").Parse(staticTempl))
+
+const staticTempl = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{if .Title}}{{.Title}}{{else if .Reference.Title}}{{.Reference.Title}}{{else}}API Reference{{end}}</title>
+  {{- if .NoIndex}}
+  <meta name="robots" content="noindex, nofollow">
+  {{- end}}
+  {{- range .Stylesheets}}
+  <link rel="stylesheet" href="{{.}}">
+  {{- end}}
+  <style>
+    body { font-family: sans-serif; margin: 0 auto; max-width: 960px; padding: 0 16px; color: #3b4151; }
+    table { border-collapse: collapse; width: 100%; }
+    th, td { border-bottom: 1px solid #ddd; padding: 4px 8px; text-align: left; vertical-align: top; }
+    code { font-size: 0.95em; }
+    .method { font-weight: bold; text-transform: uppercase; }
+    .deprecated { text-decoration: line-through; }
+  </style>
+  {{- if .CustomCSS}}
+  <style>
+    {{.CustomCSS}}
+  </style>
+  {{- end}}
+  {{- if .HeadContent}}
+  {{.HeadContent}}
+  {{- end}}
+</head>
+
+<body>
+<header>
+  <h1>{{.Reference.Title}}{{with .Reference.Version}} <small>{{.}}</small>{{end}}</h1>
+  {{- with .Reference.Description}}
+  <p>{{.}}</p>
+  {{- end}}
+  <p><a href="{{.DocURL}}">API definition</a></p>
+</header>
+
+<main>
+<h2>Operations</h2>
+{{- range .Reference.Operations}}
+<section id="{{.ID}}">
+  <h3{{if .Deprecated}} class="deprecated"{{end}}><span class="method">{{.Method}}</span> <code>{{.Path}}</code></h3>
+  {{- with .Summary}}
+  <p><strong>{{.}}</strong></p>
+  {{- end}}
+  {{- with .Description}}
+  <p>{{.}}</p>
+  {{- end}}
+  {{- with .Tags}}
+  <p>Tags: {{range $i, $tag := .}}{{if $i}}, {{end}}{{$tag}}{{end}}</p>
+  {{- end}}
+  {{- with .Parameters}}
+  <table>
+    <caption>Parameters</caption>
+    <tr><th>Name</th><th>In</th><th>Type</th><th>Description</th></tr>
+    {{- range .}}
+    <tr><td><code>{{.Name}}</code>{{if .Required}} (required){{end}}</td><td>{{.In}}</td><td>{{.Type}}</td><td>{{.Description}}</td></tr>
+    {{- end}}
+  </table>
+  {{- end}}
+  {{- with .Responses}}
+  <table>
+    <caption>Responses</caption>
+    <tr><th>Code</th><th>Type</th><th>Description</th></tr>
+    {{- range .}}
+    <tr><td>{{.Code}}</td><td>{{.Type}}</td><td>{{.Description}}</td></tr>
+    {{- end}}
+  </table>
+  {{- end}}
+</section>
+{{- end}}
+{{- with .Reference.Schemas}}
+
+<h2>Schemas</h2>
+{{- range .}}
+<section id="schema-{{.Name}}">
+  <h3>{{.Name}}</h3>
+  {{- with .Description}}
+  <p>{{.}}</p>
+  {{- end}}
+  {{- with .Properties}}
+  <table>
+    <tr><th>Property</th><th>Type</th><th>Description</th></tr>
+    {{- range .}}
+    <tr><td><code>{{.Name}}</code>{{if .Required}} (required){{end}}</td><td>{{.Type}}</td><td>{{.Description}}</td></tr>
+    {{- end}}
+  </table>
+  {{- end}}
+</section>
+{{- end}}
+{{- end}}
+</main>
+</body>
+</html>
+`
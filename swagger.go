@@ -1,205 +1,3306 @@
 package httpSwagger
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
+	"io"
+	"io/fs"
+	"log"
+	"mime"
 	"net/http"
+	"net/url"
+	"os"
+	"path"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
-	swaggerFiles "github.com/swaggo/files"
 	"github.com/swaggo/swag"
+	"gopkg.in/yaml.v2"
 )
 
-// WrapHandler wraps swaggerFiles.Handler and returns http.HandlerFunc.
+// ErrUnauthorized is returned by an Authorize hook to answer 401 Unauthorized instead of 403 Forbidden,
+// with the BasicAuth challenge. Without BasicAuth there is no challenge to send, use Unauthorized.
+var ErrUnauthorized = errors.New("httpSwagger: unauthorized")
+
+// Unauthorized returns an error for an Authorize hook answering 401 Unauthorized with the given
+// WWW-Authenticate challenge, e.g. `Bearer realm="api"`. It matches ErrUnauthorized.
+func Unauthorized(challenge string) error {
+	return &unauthorizedError{challenge: challenge}
+}
+
+// unauthorizedError is an ErrUnauthorized carrying its WWW-Authenticate challenge.
+type unauthorizedError struct {
+	challenge string
+}
+
+func (e *unauthorizedError) Error() string {
+	return ErrUnauthorized.Error()
+}
+
+func (e *unauthorizedError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+// errEmptyDoc is reported for empty API definitions.
+var errEmptyDoc = errors.New("httpSwagger: empty API definition")
+
+// WrapHandler serves Swagger UI and the API definition with the default configuration.
 var WrapHandler = Handler()
 
-// Config stores httpSwagger configuration variables.
-type Config struct {
-	// The url pointing to API definition (normally swagger.json or swagger.yaml). Default is `doc.json`.
-	URL                  string
-	DocExpansion         string
-	DomID                string
-	InstanceName         string
-	BeforeScript         template.JS
-	AfterScript          template.JS
-	Plugins              []template.JS
-	UIConfig             map[template.JS]template.JS
-	DeepLinking          bool
-	PersistAuthorization bool
-}
+// Config stores httpSwagger configuration variables.
+type Config struct {
+	// The url pointing to API definition (normally swagger.json or swagger.yaml). Default is `doc.json`.
+	URL                  string
+	DocExpansion         string
+	DomID                string
+	InstanceName         string
+	BeforeScript         template.JS
+	AfterScript          template.JS
+	Plugins              []template.JS
+	UIConfig             map[template.JS]template.JS
+	DeepLinking          bool
+	PersistAuthorization bool
+	// The format of the API definition the UI loads, either `json` or `yaml`. Swag documents are
+	// served in the other format too, next to it. Default is `json`.
+	SpecFormat string
+	// The path below the handler the API definition is served at, independently of the url the UI
+	// requests it from. Default is `doc.{SpecFormat}`.
+	SpecPath string
+	// Returns the raw API definition and its content type, bypassing the swag registry when set.
+	// Default is nil.
+	SpecProvider func(context.Context) ([]byte, string, error)
+	// The file system and path the API definition is read from, bypassing the swag registry when set.
+	// Default is nil.
+	SpecFS     fs.FS
+	SpecFSPath string
+	// Disables all request execution and authorization controls. Default is false.
+	ReadOnly bool
+	// Returns a fresh nonce for each request, applied to every script tag and to the
+	// Content-Security-Policy response header. Default is nil (no nonce).
+	CSPNonceFunc func(*http.Request) string
+	// The Cache-Control header value sent with the embedded static assets. Default is empty (no header).
+	CacheControl string
+	// The page title of the Swagger UI HTML. Default is `Swagger UI`.
+	Title string
+	// The url of the page favicon. Default is empty (the embedded favicons are used).
+	FaviconURL string
+	// Stylesheet rules emitted after the bundled swagger-ui.css. Default is empty.
+	CustomCSS template.CSS
+	// The public path the handler is served at, prefixed to the asset and relative API definition urls.
+	// When empty, it is only derived from the X-Forwarded-Prefix header with TrustForwardedHeaders.
+	// Default is empty (urls are relative to the current page).
+	BasePath string
+	// Derives the public url of the handler from the X-Forwarded-Proto, X-Forwarded-Host and
+	// X-Forwarded-Prefix request headers when BasePath is empty. Only enable this behind a proxy
+	// that sets these headers. Default is false.
+	TrustForwardedHeaders bool
+	// The url of the OAuth2 redirect page. Default is empty (the embedded `oauth2-redirect.html`
+	// next to the current page is used).
+	OAuth2RedirectURL string
+	// The initial OAuth2 client configuration passed to `ui.initOAuth`. Default is nil (not called).
+	OAuth2Config *OAuth2Config
+	// The API definitions listed in the top bar URL switcher. Default is nil.
+	URLs []URLsConfig
+	// The Swagger UI layout, either `StandaloneLayout` or `BaseLayout`. Default is `StandaloneLayout`
+	// when URLs is set and `BaseLayout` otherwise.
+	Layout string
+	// Enables the operations filter box. Default is false.
+	Filter bool
+	// The initial expression of the operations filter box; enables filtering when set. Default is empty.
+	FilterExpression string
+	// The maximum number of tagged operations displayed. Default is 0 (unlimited).
+	MaxDisplayedTags int
+	// The syntax highlighting configuration of request and response bodies. Default is nil
+	// (Swagger UI defaults).
+	SyntaxHighlight *SyntaxHighlightConfig
+	// A JavaScript function expression receiving and returning the request before it is sent.
+	RequestInterceptor template.JS
+	// A JavaScript function expression receiving and returning the response before it is rendered.
+	ResponseInterceptor template.JS
+	// The initial rendering of schemas, either `example` or `model`. Default is empty (Swagger UI default).
+	DefaultModelRendering string
+	// Shows the top bar with the API definition url input or switcher. Default is true.
+	ShowTopBar bool
+	// Makes invalid configuration values an error instead of resetting them to their defaults.
+	// Default is false.
+	StrictValidation bool
+	// Receives internal errors of the handler. Invalid configuration values are logged to it too,
+	// or to the standard logger when unset. Default is a no-op logger.
+	Logger ErrorLogger
+	// Called with "ui", "spec" or "asset" on each request for the index page, the API definition
+	// or an embedded asset, e.g. to count requests per endpoint. Default is nil.
+	MetricsHook func(event string)
+	// The url of the spec validator badge service. nil omits it (Swagger UI uses validator.swagger.io),
+	// an empty string disables validation. Default is an empty string.
+	ValidatorURL *string
+	// A JavaScript function expression receiving each model property and returning its default value.
+	ModelPropertyMacro template.JS
+	// A JavaScript function expression receiving each operation and parameter and returning the
+	// parameter default value.
+	ParameterMacro template.JS
+	// Shows the request as mutated by RequestInterceptor in the curl command. Default is true.
+	ShowMutatedRequest bool
+	// Enables the "Try it out" section of all operations by default. Default is false.
+	TryItOutEnabled bool
+	// Shows request code snippets. Default is false.
+	RequestSnippetsEnabled bool
+	// The request code snippets configuration. Default is nil (Swagger UI defaults).
+	RequestSnippets *RequestSnippetsConfig
+	// Sends cookies and other credentials with "Try it out" requests. Default is false.
+	WithCredentials bool
+	// The HTTP methods "Try it out" is enabled for. nil enables all methods, an empty slice none.
+	// Default is nil.
+	SupportedSubmitMethods []string
+	// The rules enabling or disabling "Try it out" for the operations they match, overriding
+	// SupportedSubmitMethods. The first matching rule applies. Default is nil.
+	SubmitMethodRules []SubmitMethodRule
+	// The origins allowed to fetch the API definition cross-origin, "*" allows any origin.
+	// Default is nil (no CORS headers).
+	AllowedOrigins []string
+	// The credentials required to access any endpoint of the handler. Default is nil (no authentication).
+	BasicAuth *BasicAuthConfig
+	// Called for each request before anything is served. A nil error grants access, an Unauthorized
+	// error, or ErrUnauthorized along with BasicAuth, answers 401 Unauthorized with its challenge and
+	// any other error 403 Forbidden. Default is nil (no authorization).
+	Authorize func(*http.Request) error
+	// Lets the page url query parameters override the Swagger UI configuration. Default is false.
+	QueryConfigEnabled bool
+	// The Swagger UI presets, replacing the default apis and standalone presets when set.
+	// StandaloneLayout requires SwaggerUIStandalonePreset. Default is nil.
+	Presets []template.JS
+	// Shows the duration of "Try it out" requests. Default is false.
+	DisplayRequestDuration bool
+	// The additional swag instances served at `{name}/{SpecPath}` and listed in the top bar URL
+	// switcher. Default is nil.
+	Instances []string
+	// The file system the Swagger UI assets (swagger-ui-bundle.js, swagger-ui.css, ...) are served
+	// from instead of the embedded ones. Default is nil (embedded assets).
+	AssetFS fs.FS
+	// The front-end rendering the API definition, either `swagger` (Swagger UI) or `redoc`.
+	// Default is `swagger`.
+	Renderer string
+	// The url the Redoc standalone bundle is loaded from by the `redoc` renderer. Unlike the Swagger UI
+	// assets the bundle is not embedded, to keep the module small. Default is the Redoc 2.1.5 bundle
+	// published on the Redoc CDN.
+	RedocBundleURL string
+	// The localStorage key the authorization is persisted under with PersistAuthorization, so that
+	// several UIs served on the same origin do not share it. Default is empty (Swagger UI's "authorized").
+	AuthorizationPersistKey string
+	// The path below the handler answering whether the API definition can be read and is not empty,
+	// e.g. `healthz`, with 200 or 503 and a short JSON body, the error being logged. Default is empty
+	// (disabled).
+	HealthPath string
+	// Raw HTML injected at the end of the page head, e.g. meta or preconnect tags. It is not escaped.
+	// Default is empty.
+	HeadContent template.HTML
+	// Asks search engines not to index the page, with a robots meta tag and an X-Robots-Tag header.
+	// Default is false.
+	NoIndex bool
+	// Compacts the Swagger UI configuration emitted by the built-in template, dropping the entries
+	// equal to the Swagger UI defaults. User supplied JavaScript is kept on its own lines as is.
+	// Default is false (readable output).
+	MinifyConfig bool
+	// The urls of the API definition tried in order when loading it from URL fails, e.g. a mirror.
+	// Default is nil.
+	FallbackURLs []string
+	// Indents the JSON API definition served, for human readers. Default is false (served as is).
+	PrettyJSON bool
+	// Serves the swag document as YAML to requests accepting application/x-yaml, application/yaml
+	// or text/yaml, and as JSON otherwise, at SpecPath. Default is false.
+	NegotiateSpecFormat bool
+	// Redirects plain HTTP requests to HTTPS before serving anything. The scheme is taken from the
+	// X-Forwarded-Proto header only with TrustForwardedHeaders. Default is false.
+	RequireHTTPS bool
+	// Shows the vendor extensions (`x-` fields) of operations, parameters, responses and schemas.
+	// Default is false.
+	ShowExtensions bool
+	// Shows the pattern, maxLength, minLength, maximum and minimum fields of parameters.
+	// Default is false.
+	ShowCommonExtensions bool
+	// The expansion depth of the models section, -1 hides it. nil omits it (Swagger UI uses 1).
+	// Default is nil.
+	DefaultModelsExpandDepth *int
+	// The expansion depth of the schemas of the operations, 0 collapses them. nil omits it (Swagger UI
+	// uses 1). Default is nil.
+	DefaultModelExpandDepth *int
+	// Shows the models section, listing the schemas of the API definition. The schemas referenced
+	// by operations are still shown. Hiding it sets DefaultModelsExpandDepth to -1. Default is true.
+	ShowModels bool
+	// Shows the link to the API definition under its title and the url input of the top bar.
+	// Default is true.
+	ShowDownloadButton bool
+	// The template of the index page replacing the built-in one, executed with a TemplateData.
+	// Default is nil (built-in template of the Renderer).
+	Template *template.Template
+	// The url of a swagger-ui-dist release the assets are loaded from instead of the embedded ones,
+	// which are not served then. Default is empty (embedded assets).
+	CDNBaseURL string
+	// The subresource integrity metadata of the assets loaded by the page by file name, e.g.
+	// "swagger-ui-bundle.js": "sha384-...", for CDN assets. The metadata of the embedded assets
+	// is computed. Default is nil.
+	AssetIntegrity map[string]string
+	// The urls of external stylesheets linked after the Swagger UI one, e.g. web fonts. Default is nil.
+	Stylesheets []string
+	// The urls of external scripts loaded after the Swagger UI bundle, before it is started.
+	// Default is nil.
+	Scripts []string
+	// Serves a reference page rendered from the API definition on the server instead of Swagger UI,
+	// for environments where running its JavaScript is not allowed. Default is false.
+	StaticRender bool
+	// The server urls replacing the ones of the served API definition, the servers of OpenAPI 3
+	// definitions or the schemes, host and basePath of Swagger 2.0 ones from the first url.
+	// Relative urls are resolved against the public url of the request. Default is nil.
+	OverrideSpecServers []string
+	// The default values of the variables of the OpenAPI 3 servers by name, also added to their enum
+	// and declared for the servers whose url uses them. Default is nil.
+	ServerVariables map[string]string
+	// The sorting of the operations of each tag, either `"alpha"`, `"method"` or a JavaScript
+	// comparison function expression. Default is empty (definition order).
+	OperationsSorter template.JS
+	// The sorting of the tags, either `"alpha"` or a JavaScript comparison function expression.
+	// Default is empty (definition order).
+	TagsSorter template.JS
+	// Reloads the page when the API definition changes, polling its ETag. It is meant for
+	// development only. Default is false.
+	DevReload bool
+	// Inlines the API definition in the page instead of fetching it from its url, e.g. when the
+	// Content-Security-Policy connect-src does not allow it. Default is false.
+	InlineSpec bool
+	// The file system of the assets served instead of the embedded ones with the same name,
+	// the other ones are still served from the embedded assets. Default is nil.
+	AssetOverrides http.FileSystem
+	// The time in milliseconds after which the requests sent by Swagger UI, including the one
+	// loading the API definition, are aborted with a timeout error. Default is 0 (no timeout).
+	RequestTimeoutMs int
+	// Returns the API definition served, inlined or rendered, receiving the loaded one, e.g. to
+	// remove internal operations. It runs for each request. Default is nil.
+	SpecTransform func(ctx context.Context, raw []byte) ([]byte, error)
+	// The tags whose operations are removed from the API definition served, inlined or rendered,
+	// along with the components only they referenced. Default is nil.
+	HiddenTags []string
+	// The size in bytes of the JSON encoding of the examples of the API definition above which they
+	// are replaced with a note, keeping the page responsive. Default is 0 (unlimited).
+	MaxExampleBytes int
+	// The Content-Security-Policy header of the index page with CSPNonceFunc, {nonce} is replaced
+	// with the nonce of the request. Default is "script-src 'nonce-{nonce}'".
+	ContentSecurityPolicy string
+	// The language of the page, e.g. "de" or "pt-BR". Default is empty (English).
+	Locale string
+	// The translations of the Swagger UI labels by locale, JavaScript objects mapping the English
+	// texts to the translated ones, e.g. "de": `{"Authorize": "Autorisieren"}`. The ones of the
+	// Locale, or of its language, are applied by a translation plugin. Default is nil.
+	LocaleData map[string]template.JS
+	// Serves the resolved configuration as JSON at `config.json` below the handler, for debugging,
+	// unless the API definition is served there. The functions, interfaces and template it holds,
+	// the basic authentication password and the OAuth2 client secret and additional query string
+	// parameters are omitted. Default is false.
+	ExposeConfigEndpoint bool
+	// The color theme of Swagger UI, either `light`, `dark` or `auto` (the dark one when the browser
+	// prefers a dark color scheme). Default is `light`.
+	Theme string
+}
+
+// SwaggerUIVersion is the version of the embedded Swagger UI assets. It renders Swagger 2.0 and
+// OpenAPI 3.0 definitions, OpenAPI 3.1 ones need a 5.x release loaded with CDN.
+const SwaggerUIVersion = "4.11.0"
+
+// JSDelivrURL returns the url of the given swagger-ui-dist release on the jsDelivr CDN,
+// e.g. JSDelivrURL(SwaggerUIVersion) for the release matching the embedded assets.
+func JSDelivrURL(version string) string {
+	return "https://cdn.jsdelivr.net/npm/swagger-ui-dist@" + version
+}
+
+// BasicAuthConfig stores the credentials of the HTTP basic authentication guard.
+type BasicAuthConfig struct {
+	Username string
+	Password string
+}
+
+// RequestSnippetsConfig stores the Swagger UI request code snippets configuration.
+type RequestSnippetsConfig struct {
+	// The snippet generators by name, e.g. curl_bash, curl_powershell, curl_cmd.
+	Generators map[string]RequestSnippetGenerator `json:"generators,omitempty"`
+	// Expands the snippets section by default.
+	DefaultExpanded bool `json:"defaultExpanded"`
+	// The names of the generators shown. Empty shows all of them.
+	Languages []string `json:"languages,omitempty"`
+}
+
+// RequestSnippetGenerator stores a Swagger UI request code snippet generator.
+type RequestSnippetGenerator struct {
+	Title  string `json:"title"`
+	Syntax string `json:"syntax"`
+}
+
+// ErrorLogger is the interface internal errors are reported to. It is satisfied by *log.Logger.
+type ErrorLogger interface {
+	Printf(format string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Printf(string, ...interface{}) {}
+
+// SyntaxHighlightConfig stores the Swagger UI syntax highlighting configuration.
+type SyntaxHighlightConfig struct {
+	Activate bool `json:"activate"`
+	// One of agate, arta, monokai, nord, obsidian, tomorrow-night.
+	Theme string `json:"theme,omitempty"`
+}
+
+// URLsConfig stores an API definition listed in the top bar URL switcher.
+type URLsConfig struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+}
+
+// SubmitMethodRule enables or disables "Try it out" for the operations matching all of its patterns.
+// The `*` wildcard of a pattern matches any characters, including slashes, and an empty pattern
+// matches any operation.
+type SubmitMethodRule struct {
+	// The path of the operations, e.g. "/admin/*".
+	Path string `json:"path,omitempty"`
+	// The HTTP method of the operations, case-insensitive, e.g. "post".
+	Method string `json:"method,omitempty"`
+	// A tag of the operations, e.g. "staging".
+	Tag string `json:"tag,omitempty"`
+	// Enables "Try it out" for the matching operations, when true, or disables it.
+	Enabled bool `json:"enabled"`
+}
+
+// OAuth2Config stores the Swagger UI OAuth2 client configuration.
+type OAuth2Config struct {
+	ClientID                                  string            `json:"clientId,omitempty"`
+	ClientSecret                              string            `json:"clientSecret,omitempty"`
+	Realm                                     string            `json:"realm,omitempty"`
+	AppName                                   string            `json:"appName,omitempty"`
+	ScopeSeparator                            string            `json:"scopeSeparator,omitempty"`
+	Scopes                                    []string          `json:"scopes,omitempty"`
+	AdditionalQueryStringParams               map[string]string `json:"additionalQueryStringParams,omitempty"`
+	UseBasicAuthenticationWithAccessCodeGrant bool              `json:"useBasicAuthenticationWithAccessCodeGrant,omitempty"`
+	UsePkceWithAuthorizationCodeGrant         bool              `json:"usePkceWithAuthorizationCodeGrant,omitempty"`
+}
+
+// URL presents the url pointing to API definition (normally swagger.json or swagger.yaml).
+func URL(url string) func(*Config) {
+	return func(c *Config) {
+		c.URL = url
+	}
+}
+
+// DeepLinking true, false. Deep links (e.g. `#/pet/addPet`) are resolved by the apis preset,
+// keep "SwaggerUIBundle.presets.apis" when replacing the presets.
+func DeepLinking(deepLinking bool) func(*Config) {
+	return func(c *Config) {
+		c.DeepLinking = deepLinking
+	}
+}
+
+// DocExpansion list, full, none.
+func DocExpansion(docExpansion string) func(*Config) {
+	return func(c *Config) {
+		c.DocExpansion = docExpansion
+	}
+}
+
+// DomID sets the id of the element Swagger UI is mounted in, with or without the leading `#`,
+// e.g. `swagger-ui` or `#swagger-ui`.
+func DomID(domID string) func(*Config) {
+	return func(c *Config) {
+		c.DomID = domID
+	}
+}
+
+// InstanceName set the instance name that was used to generate the swagger documents
+// Defaults to swag.Name ("swagger").
+func InstanceName(name string) func(*Config) {
+	return func(c *Config) {
+		c.InstanceName = name
+	}
+}
+
+// CDN loads the Swagger UI assets from the swagger-ui-dist release at baseURL instead of the
+// embedded ones, e.g. CDN(JSDelivrURL(SwaggerUIVersion)). The OAuth2 redirect page is still served.
+func CDN(baseURL string) func(*Config) {
+	return func(c *Config) {
+		c.CDNBaseURL = baseURL
+	}
+}
+
+// Stylesheets links external stylesheets in order, e.g. "https://fonts.googleapis.com/css2?family=Inter".
+func Stylesheets(urls ...string) func(*Config) {
+	return func(c *Config) {
+		c.Stylesheets = urls
+	}
+}
+
+// Scripts loads external scripts in order after the Swagger UI bundle, e.g. a plugin.
+func Scripts(urls ...string) func(*Config) {
+	return func(c *Config) {
+		c.Scripts = urls
+	}
+}
+
+// OverrideSpecServers replaces the servers of the served API definition, so that requests sent
+// from Swagger UI reach the host it is served from behind a proxy. Relative urls, e.g. "/api/v1",
+// are resolved against the public url of the request, see TrustForwardedHeaders.
+func OverrideSpecServers(urls ...string) func(*Config) {
+	return func(c *Config) {
+		c.OverrideSpecServers = urls
+	}
+}
+
+// ServerVariables sets the default values of the variables of the served OpenAPI 3 servers,
+// e.g. {"region": "eu"} for the url "https://{region}.api.example.com".
+func ServerVariables(vars map[string]string) func(*Config) {
+	return func(c *Config) {
+		c.ServerVariables = vars
+	}
+}
+
+// StaticRender serves a plain HTML reference page of the operations and schemas of the API
+// definition, rendered on the server, instead of Swagger UI. Defaults to false.
+func StaticRender(static bool) func(*Config) {
+	return func(c *Config) {
+		c.StaticRender = static
+	}
+}
+
+// AssetIntegrity sets the subresource integrity metadata of the CDN assets by file name,
+// i.e. swagger-ui.css, swagger-ui-bundle.js, swagger-ui-standalone-preset.js and redoc.standalone.js.
+func AssetIntegrity(integrity map[string]string) func(*Config) {
+	return func(c *Config) {
+		c.AssetIntegrity = integrity
+	}
+}
+
+// Template replaces the built-in index page template. It is executed with a TemplateData,
+// holding the Config fields along with the request dependent urls.
+func Template(t *template.Template) func(*Config) {
+	return func(c *Config) {
+		c.Template = t
+	}
+}
+
+// DefaultModelsExpandDepth sets the expansion depth of the models section, -1 hides it.
+func DefaultModelsExpandDepth(depth int) func(*Config) {
+	return func(c *Config) {
+		c.DefaultModelsExpandDepth = &depth
+	}
+}
+
+// ShowModels shows the models section, the schemas are still shown in the operations
+// referencing them. Hiding it sets the DefaultModelsExpandDepth to -1.
+// Defaults to true.
+func ShowModels(show bool) func(*Config) {
+	return func(c *Config) {
+		c.ShowModels = show
+	}
+}
+
+// DefaultModelExpandDepth sets the expansion depth of the schemas shown in the operations.
+func DefaultModelExpandDepth(depth int) func(*Config) {
+	return func(c *Config) {
+		c.DefaultModelExpandDepth = &depth
+	}
+}
+
+// DisableDefaultModelExpansion collapses the schemas shown in the operations, it is the same as
+// DefaultModelExpandDepth(0).
+func DisableDefaultModelExpansion() func(*Config) {
+	return DefaultModelExpandDepth(0)
+}
+
+// ModelRendering sets how the schemas of the operations are rendered, mode is either "example"
+// or "model" ("schema" is an alias of it), expanded up to expandDepth levels. Invalid modes are
+// reported like an invalid DefaultModelRendering.
+func ModelRendering(mode string, expandDepth int) func(*Config) {
+	return func(c *Config) {
+		if mode == "schema" {
+			mode = "model"
+		}
+
+		c.DefaultModelRendering = mode
+		c.DefaultModelExpandDepth = &expandDepth
+	}
+}
+
+// ShowDownloadButton shows the link to the API definition under its title and the url input of
+// the top bar. Hiding them only makes the definition less obvious to download, it is still served.
+// Defaults to true.
+func ShowDownloadButton(show bool) func(*Config) {
+	return func(c *Config) {
+		c.ShowDownloadButton = show
+	}
+}
+
+// HideModels hides the models section, it is the same as DefaultModelsExpandDepth(-1).
+func HideModels() func(*Config) {
+	return DefaultModelsExpandDepth(-1)
+}
+
+// ShowExtensions shows the vendor extensions (`x-` fields).
+// Defaults to false.
+func ShowExtensions(show bool) func(*Config) {
+	return func(c *Config) {
+		c.ShowExtensions = show
+	}
+}
+
+// ShowCommonExtensions shows the pattern, length and range fields of parameters.
+// Defaults to false.
+func ShowCommonExtensions(show bool) func(*Config) {
+	return func(c *Config) {
+		c.ShowCommonExtensions = show
+	}
+}
+
+// RequireHTTPS redirects plain HTTP requests to HTTPS with 308 Permanent Redirect. Behind a TLS
+// terminating proxy, enable TrustForwardedHeaders too so that X-Forwarded-Proto is honoured.
+func RequireHTTPS(require bool) func(*Config) {
+	return func(c *Config) {
+		c.RequireHTTPS = require
+	}
+}
+
+// NegotiateSpecFormat serves the swag document as YAML or JSON depending on the Accept request header.
+// Defaults to false.
+func NegotiateSpecFormat(negotiate bool) func(*Config) {
+	return func(c *Config) {
+		c.NegotiateSpecFormat = negotiate
+	}
+}
+
+// PrettyJSON indents the JSON API definition served.
+// Defaults to false.
+func PrettyJSON(pretty bool) func(*Config) {
+	return func(c *Config) {
+		c.PrettyJSON = pretty
+	}
+}
+
+// FallbackURLs sets the urls of the API definition tried in order when loading it from URL fails.
+func FallbackURLs(urls ...string) func(*Config) {
+	return func(c *Config) {
+		c.FallbackURLs = urls
+	}
+}
+
+// MinifyConfig compacts the emitted Swagger UI configuration.
+// Defaults to false.
+func MinifyConfig(minify bool) func(*Config) {
+	return func(c *Config) {
+		c.MinifyConfig = minify
+	}
+}
+
+// NoIndex asks search engines not to index nor follow the UI page.
+// Defaults to false.
+func NoIndex(noIndex bool) func(*Config) {
+	return func(c *Config) {
+		c.NoIndex = noIndex
+	}
+}
+
+// HeadContent injects raw HTML at the end of the page head, e.g. `<link rel="preconnect" href="...">`.
+// The content is not escaped, it must not contain untrusted input.
+func HeadContent(content string) func(*Config) {
+	return func(c *Config) {
+		c.HeadContent = template.HTML(content)
+	}
+}
+
+// HealthPath sets the path below the handler serving the health of the API definition, e.g. "healthz".
+func HealthPath(path string) func(*Config) {
+	return func(c *Config) {
+		c.HealthPath = path
+	}
+}
+
+// ExposeConfigEndpoint serves the resolved configuration as JSON at `config.json` below the handler,
+// to check which options took effect. It discloses the configuration, keep it off in production.
+func ExposeConfigEndpoint(expose bool) func(*Config) {
+	return func(c *Config) {
+		c.ExposeConfigEndpoint = expose
+	}
+}
+
+// AuthorizationPersistKey sets the localStorage key the authorization is persisted under,
+// e.g. "orders-api-authorized".
+func AuthorizationPersistKey(key string) func(*Config) {
+	return func(c *Config) {
+		c.AuthorizationPersistKey = key
+	}
+}
+
+// PersistAuthorization Persist authorization information over browser close/refresh.
+// Defaults to false.
+func PersistAuthorization(persistAuthorization bool) func(*Config) {
+	return func(c *Config) {
+		c.PersistAuthorization = persistAuthorization
+	}
+}
+
+// Plugins specifies additional plugins to load into Swagger UI.
+func Plugins(plugins []string) func(*Config) {
+	return func(c *Config) {
+		vs := make([]template.JS, len(plugins))
+		for i, v := range plugins {
+			vs[i] = template.JS(v)
+		}
+		c.Plugins = vs
+	}
+}
+
+// Presets replaces the default Swagger UI presets, e.g. "SwaggerUIBundle.presets.apis".
+// Keep "SwaggerUIStandalonePreset" when using StandaloneLayout.
+func Presets(presets []string) func(*Config) {
+	return func(c *Config) {
+		vs := make([]template.JS, len(presets))
+		for i, v := range presets {
+			vs[i] = template.JS(v)
+		}
+		c.Presets = vs
+	}
+}
+
+// UIConfig specifies additional SwaggerUIBundle config object properties.
+func UIConfig(props map[string]string) func(*Config) {
+	return func(c *Config) {
+		vs := make(map[template.JS]template.JS, len(props))
+		for k, v := range props {
+			vs[template.JS(k)] = template.JS(v)
+		}
+		c.UIConfig = vs
+	}
+}
+
+// ReadOnly disables "Try it out", request execution and authorization controls.
+// Defaults to false.
+func ReadOnly(readOnly bool) func(*Config) {
+	return func(c *Config) {
+		c.ReadOnly = readOnly
+	}
+}
+
+// CSPNonceFunc sets the function generating the Content-Security-Policy script nonce
+// for each request.
+func CSPNonceFunc(fn func(*http.Request) string) func(*Config) {
+	return func(c *Config) {
+		c.CSPNonceFunc = fn
+	}
+}
+
+// CacheControl sets the Cache-Control header sent with the embedded static assets,
+// e.g. "public, max-age=86400".
+func CacheControl(cacheControl string) func(*Config) {
+	return func(c *Config) {
+		c.CacheControl = cacheControl
+	}
+}
+
+// Title sets the page title of the Swagger UI HTML.
+// Defaults to "Swagger UI".
+func Title(title string) func(*Config) {
+	return func(c *Config) {
+		c.Title = title
+	}
+}
+
+// FaviconURL sets the url of the page favicon, replacing the embedded Swagger favicons.
+func FaviconURL(url string) func(*Config) {
+	return func(c *Config) {
+		c.FaviconURL = url
+	}
+}
+
+// CustomCSS holds CSS rules emitted into the page head, after the bundled swagger-ui.css.
+func CustomCSS(css string) func(*Config) {
+	return func(c *Config) {
+		c.CustomCSS = template.CSS(css)
+	}
+}
+
+// OAuth2RedirectURL sets the url of the OAuth2 redirect page, for when the embedded
+// `oauth2-redirect.html` is not reachable next to the index page (e.g. behind a path-rewriting proxy).
+func OAuth2RedirectURL(url string) func(*Config) {
+	return func(c *Config) {
+		c.OAuth2RedirectURL = url
+	}
+}
+
+// OAuth2 configures the initial OAuth2 client configuration, passed to `ui.initOAuth`.
+func OAuth2(fn func(*OAuth2Config)) func(*Config) {
+	return func(c *Config) {
+		if c.OAuth2Config == nil {
+			c.OAuth2Config = &OAuth2Config{}
+		}
+		fn(c.OAuth2Config)
+	}
+}
+
+// BasicAuth requires the given credentials to access the handler, using HTTP basic authentication.
+func BasicAuth(username, password string) func(*Config) {
+	return func(c *Config) {
+		c.BasicAuth = &BasicAuthConfig{Username: username, Password: password}
+	}
+}
+
+// Authorize sets a hook deciding whether a request may access the handler, e.g. by checking a session.
+// Returning Unauthorized, or ErrUnauthorized along with BasicAuth, answers 401 Unauthorized with a
+// WWW-Authenticate challenge, any other error 403 Forbidden.
+func Authorize(fn func(*http.Request) error) func(*Config) {
+	return func(c *Config) {
+		c.Authorize = fn
+	}
+}
+
+// URLs sets the API definitions listed in the top bar URL switcher.
+func URLs(urls []URLsConfig) func(*Config) {
+	return func(c *Config) {
+		c.URLs = urls
+	}
+}
+
+// InstanceURLs serves the API definitions of the given swag instances at `{name}/doc.json`
+// (or `{name}/{SpecPath}`) below the handler and lists them in the top bar URL switcher.
+// The names must be listed explicitly: the swag registry can only be looked up by name, it does
+// not list the registered instances. HandlerWithError checks that they are all registered.
+func InstanceURLs(names ...string) func(*Config) {
+	return func(c *Config) {
+		c.Instances = names
+	}
+}
+
+// Layout StandaloneLayout, BaseLayout.
+func Layout(layout string) func(*Config) {
+	return func(c *Config) {
+		c.Layout = layout
+	}
+}
+
+// Filter enables the filter box to filter operations by tag.
+// Defaults to false.
+func Filter(filter bool) func(*Config) {
+	return func(c *Config) {
+		c.Filter = filter
+	}
+}
+
+// FilterExpression enables the filter box, filled with the given expression.
+func FilterExpression(expr string) func(*Config) {
+	return func(c *Config) {
+		c.FilterExpression = expr
+	}
+}
+
+// MaxDisplayedTags limits the number of tagged operations displayed; zero means unlimited.
+func MaxDisplayedTags(n int) func(*Config) {
+	return func(c *Config) {
+		c.MaxDisplayedTags = n
+	}
+}
+
+// SyntaxHighlight true, false.
+func SyntaxHighlight(activate bool) func(*Config) {
+	return func(c *Config) {
+		if c.SyntaxHighlight == nil {
+			c.SyntaxHighlight = &SyntaxHighlightConfig{}
+		}
+		c.SyntaxHighlight.Activate = activate
+	}
+}
+
+// SyntaxHighlightTheme agate, arta, monokai, nord, obsidian, tomorrow-night.
+// It activates syntax highlighting unless it was disabled with SyntaxHighlight.
+func SyntaxHighlightTheme(theme string) func(*Config) {
+	return func(c *Config) {
+		if c.SyntaxHighlight == nil {
+			c.SyntaxHighlight = &SyntaxHighlightConfig{Activate: true}
+		}
+		c.SyntaxHighlight.Theme = theme
+	}
+}
+
+// SpecTransform sets the function changing the API definition before it is served, inlined in the
+// page with InlineSpec or rendered with StaticRender, after OverrideSpecServers and ServerVariables.
+// It receives the request context, an error answers 500.
+func SpecTransform(fn func(ctx context.Context, raw []byte) ([]byte, error)) func(*Config) {
+	return func(c *Config) {
+		c.SpecTransform = fn
+	}
+}
+
+// HiddenTags removes the operations with one of the tags from the API definition served, inlined
+// in the page with InlineSpec or rendered with StaticRender, e.g. to only document the public
+// operations. The paths left without operations, the tags and the components only referenced by
+// the removed operations are removed too. It runs before the SpecTransform, empty tags are ignored.
+func HiddenTags(tags ...string) func(*Config) {
+	return func(c *Config) {
+		for _, tag := range tags {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				c.HiddenTags = append(c.HiddenTags, tag)
+			}
+		}
+	}
+}
+
+// MaxExampleBytes replaces the examples of the API definition whose JSON encoding is larger than
+// n bytes with an "example truncated" note, as rendering megabyte-scale examples freezes the browser.
+// It applies to the API definition served, inlined or rendered, before the SpecTransform.
+// Defaults to 0 (unlimited).
+func MaxExampleBytes(n int) func(*Config) {
+	return func(c *Config) {
+		c.MaxExampleBytes = n
+	}
+}
+
+// transformSpec removes the HiddenTags, truncates the examples larger than MaxExampleBytes and applies the SpecTransform to the API definition doc.
+func (c *Config) transformSpec(ctx context.Context, doc []byte, contentType string) ([]byte, error) {
+	if len(c.HiddenTags) > 0 {
+		var err error
+		if doc, err = hideTags(doc, contentType, c.HiddenTags); err != nil {
+			return nil, fmt.Errorf("httpSwagger: hiding API definition tags: %w", err)
+		}
+	}
+
+	if c.MaxExampleBytes > 0 {
+		var err error
+		if doc, err = truncateExamples(doc, contentType, c.MaxExampleBytes); err != nil {
+			return nil, fmt.Errorf("httpSwagger: truncating API definition examples: %w", err)
+		}
+	}
+
+	if c.SpecTransform == nil {
+		return doc, nil
+	}
+
+	doc, err := c.SpecTransform(ctx, doc)
+	if err != nil {
+		return nil, fmt.Errorf("httpSwagger: transforming API definition: %w", err)
+	}
+
+	return doc, nil
+}
+
+// RequestTimeoutMs aborts the requests sent by Swagger UI that take longer than ms milliseconds,
+// answering them with a timeout error instead of waiting forever for a hung API, e.g. with Try it out.
+// It wraps the RequestInterceptor, if any. Defaults to 0 (no timeout).
+func RequestTimeoutMs(ms int) func(*Config) {
+	return func(c *Config) {
+		c.RequestTimeoutMs = ms
+	}
+}
+
+// RequestInterceptor holds a JavaScript function expression, e.g. `(req) => req`, that may
+// modify each request sent by Swagger UI and must return it.
+func RequestInterceptor(js string) func(*Config) {
+	return func(c *Config) {
+		c.RequestInterceptor = template.JS(js)
+	}
+}
+
+// ResponseInterceptor holds a JavaScript function expression, e.g. `(res) => res`, that may
+// modify each response received by Swagger UI and must return it.
+func ResponseInterceptor(js string) func(*Config) {
+	return func(c *Config) {
+		c.ResponseInterceptor = template.JS(js)
+	}
+}
+
+// Locale sets the language of the page, e.g. "de", the labels are translated with the LocaleData
+// registered for it.
+func Locale(locale string) func(*Config) {
+	return func(c *Config) {
+		c.Locale = locale
+	}
+}
+
+// LocaleData registers the translations of the Swagger UI labels by locale, each a JavaScript
+// object mapping the English texts to the translated ones, e.g. `{"Authorize": "Autorisieren"}`.
+func LocaleData(data map[string]string) func(*Config) {
+	return func(c *Config) {
+		c.LocaleData = make(map[string]template.JS, len(data))
+		for locale, v := range data {
+			c.LocaleData[locale] = template.JS(v)
+		}
+	}
+}
+
+// ContentSecurityPolicy sets the Content-Security-Policy header of the index page sent with
+// CSPNonceFunc, in which {nonce} is replaced with the nonce of the request.
+func ContentSecurityPolicy(policy string) func(*Config) {
+	return func(c *Config) {
+		c.ContentSecurityPolicy = policy
+	}
+}
+
+// OfflineContentSecurityPolicy is the Content-Security-Policy the index page is served with in
+// OfflineMode. It allows the inline styles of Swagger UI and its data: url images.
+const OfflineContentSecurityPolicy = "default-src 'self'; script-src 'nonce-{nonce}'; " +
+	"style-src 'self' 'unsafe-inline'; img-src 'self' data:"
+
+// OfflineMode serves a page that loads nothing but the handler's own urls, for air-gapped
+// networks and locked-down pages: the API definition is inlined, the assets are the embedded
+// ones, the validator badge is disabled and the scripts get a random nonce, unless a CSPNonceFunc
+// is set, with the OfflineContentSecurityPolicy header
+//
+//	Content-Security-Policy: default-src 'self'; script-src 'nonce-{nonce}'; style-src 'self' 'unsafe-inline'; img-src 'self' data:
+//
+// The Redoc bundle, which is not embedded, is loaded from "./redoc.standalone.js" that must be
+// served with AssetOverrides or AssetFS. The options following it may change these settings.
+func OfflineMode() func(*Config) {
+	return func(c *Config) {
+		c.InlineSpec = true
+		c.CDNBaseURL = ""
+		c.ValidatorURL = new(string)
+		c.ContentSecurityPolicy = OfflineContentSecurityPolicy
+		c.RedocBundleURL = offlineRedocBundleURL
+
+		if c.CSPNonceFunc == nil {
+			c.CSPNonceFunc = randomNonce
+		}
+	}
+}
+
+// randomNonce returns a random CSP nonce.
+func randomNonce(*http.Request) string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// InlineSpec embeds the API definition in the index page, so that Swagger UI does not fetch it.
+// YAML definitions are converted to JSON. Defaults to false.
+func InlineSpec(inline bool) func(*Config) {
+	return func(c *Config) {
+		c.InlineSpec = inline
+	}
+}
+
+// DevReload reloads Swagger UI when the API definition changes, e.g. once it is regenerated with
+// swag and the server restarted, by polling it every 2 seconds. It should not be enabled in production.
+// Defaults to false.
+func DevReload(reload bool) func(*Config) {
+	return func(c *Config) {
+		c.DevReload = reload
+	}
+}
+
+// OperationsSorter sorts the operations of each tag, "alpha" by path, "method" by HTTP method,
+// or with a JavaScript comparison function expression, e.g. `(a, b) => a.get("path").localeCompare(b.get("path"))`.
+func OperationsSorter(sorter string) func(*Config) {
+	return func(c *Config) {
+		c.OperationsSorter = sorterJS(sorter, "alpha", "method")
+	}
+}
+
+// TagsSorter sorts the tags, "alpha" by name or with a JavaScript comparison function expression.
+func TagsSorter(sorter string) func(*Config) {
+	return func(c *Config) {
+		c.TagsSorter = sorterJS(sorter, "alpha")
+	}
+}
+
+// sorterJS returns the JavaScript expression of a sorter, quoting the named ones.
+func sorterJS(sorter string, names ...string) template.JS {
+	if contains(names, sorter) {
+		return template.JS(strconv.Quote(sorter))
+	}
+
+	return template.JS(sorter)
+}
+
+// SpecProvider sets the function returning the raw API definition and its content type,
+// served at the doc path instead of the swagger document registered in swag.
+// It receives the request context and should return once it is done.
+func SpecProvider(fn func(context.Context) ([]byte, string, error)) func(*Config) {
+	return func(c *Config) {
+		c.SpecProvider = fn
+	}
+}
+
+// SpecFS sets the file system and path of the API definition (e.g. an embed.FS holding
+// openapi.yaml), served at the doc path instead of the swagger document registered in swag.
+func SpecFS(fsys fs.FS, path string) func(*Config) {
+	return func(c *Config) {
+		c.SpecFS = fsys
+		c.SpecFSPath = path
+	}
+}
+
+// Theme sets the color theme of Swagger UI, either "light", "dark" or "auto". The dark theme is an
+// overlay of the bundled stylesheet inlined in the page, "auto" applies it when the browser prefers
+// a dark color scheme. The CustomCSS still applies after it.
+func Theme(theme string) func(*Config) {
+	return func(c *Config) {
+		c.Theme = theme
+	}
+}
+
+// Renderer sets the front-end rendering the API definition, either "swagger" or "redoc".
+func Renderer(renderer string) func(*Config) {
+	return func(c *Config) {
+		c.Renderer = renderer
+	}
+}
+
+// RedocBundleURL sets the url the Redoc standalone bundle is loaded from, e.g. to serve it
+// from AssetOverrides as "./redoc.standalone.js". The bundle is not embedded: by default it is
+// the Redoc 2.1.5 one of the Redoc CDN, whose integrity metadata can be set with AssetIntegrity
+// as "redoc.standalone.js".
+func RedocBundleURL(url string) func(*Config) {
+	return func(c *Config) {
+		c.RedocBundleURL = url
+	}
+}
+
+// AssetOverrides sets a file system of assets replacing the embedded ones, e.g. a patched
+// swagger-ui.css, the assets it does not hold are served from the embedded ones.
+func AssetOverrides(fsys http.FileSystem) func(*Config) {
+	return func(c *Config) {
+		c.AssetOverrides = fsys
+	}
+}
+
+// AssetFS sets the file system the Swagger UI assets are served from, e.g. an embed.FS holding
+// the dist directory of another Swagger UI release. Files missing from it are not found.
+func AssetFS(fsys fs.FS) func(*Config) {
+	return func(c *Config) {
+		c.AssetFS = fsys
+	}
+}
+
+// DefaultModelRendering example, model.
+func DefaultModelRendering(rendering string) func(*Config) {
+	return func(c *Config) {
+		c.DefaultModelRendering = rendering
+	}
+}
+
+// StrictValidation makes HandlerWithError return an error, and Handler panic, on invalid
+// configuration values instead of resetting them to their defaults with a logged warning.
+// Defaults to false.
+func StrictValidation(strict bool) func(*Config) {
+	return func(c *Config) {
+		c.StrictValidation = strict
+	}
+}
+
+// BasePath sets the public path the handler is served at (e.g. "/docs/swagger/"), for when
+// it sits behind a path-rewriting reverse proxy. Without it, the X-Forwarded-Prefix header set by
+// the proxy is ignored unless TrustForwardedHeaders is enabled.
+func BasePath(basePath string) func(*Config) {
+	return func(c *Config) {
+		c.BasePath = basePath
+	}
+}
+
+// TrustForwardedHeaders derives the public url of the handler from the X-Forwarded-Proto,
+// X-Forwarded-Host and X-Forwarded-Prefix request headers. Only enable this behind a proxy that
+// sets or strips these headers, as clients can spoof them otherwise.
+// Defaults to false.
+func TrustForwardedHeaders(trust bool) func(*Config) {
+	return func(c *Config) {
+		c.TrustForwardedHeaders = trust
+	}
+}
+
+// ShowTopBar shows the top bar with the API definition url input or switcher. Hiding it
+// also switches to the BaseLayout.
+// Defaults to true.
+func ShowTopBar(show bool) func(*Config) {
+	return func(c *Config) {
+		c.ShowTopBar = show
+	}
+}
+
+// Logger sets the logger internal errors of the handler are reported to.
+func Logger(l ErrorLogger) func(*Config) {
+	return func(c *Config) {
+		c.Logger = l
+	}
+}
+
+// MetricsHook sets the function called with "ui", "spec" or "asset" on each request for
+// the index page, the API definition or an embedded asset.
+func MetricsHook(fn func(event string)) func(*Config) {
+	return func(c *Config) {
+		c.MetricsHook = fn
+	}
+}
+
+// ValidatorURL sets the url of the spec validator badge service; an empty url disables validation.
+func ValidatorURL(url string) func(*Config) {
+	return func(c *Config) {
+		c.ValidatorURL = &url
+	}
+}
+
+// DisableValidator disables the spec validator badge.
+func DisableValidator() func(*Config) {
+	return ValidatorURL("")
+}
+
+// ModelPropertyMacro holds a JavaScript function expression, e.g. `(property) => property.default`,
+// returning the default value of each model property.
+func ModelPropertyMacro(js string) func(*Config) {
+	return func(c *Config) {
+		c.ModelPropertyMacro = template.JS(js)
+	}
+}
+
+// ParameterMacro holds a JavaScript function expression, e.g. `(operation, parameter) => parameter.default`,
+// returning the default value of each operation parameter.
+func ParameterMacro(js string) func(*Config) {
+	return func(c *Config) {
+		c.ParameterMacro = template.JS(js)
+	}
+}
+
+// ShowMutatedRequest shows the request as mutated by the request interceptor, instead of the
+// request as built by Swagger UI, in the curl command.
+// Defaults to true.
+func ShowMutatedRequest(show bool) func(*Config) {
+	return func(c *Config) {
+		c.ShowMutatedRequest = show
+	}
+}
+
+// TryItOutEnabled enables the "Try it out" section of all operations by default.
+// Defaults to false.
+func TryItOutEnabled(enabled bool) func(*Config) {
+	return func(c *Config) {
+		c.TryItOutEnabled = enabled
+	}
+}
+
+// QueryConfigEnabled lets the page url query parameters, e.g. `?url=...`, override the Swagger UI
+// configuration. Enabling it allows anyone sharing a link to load an arbitrary API definition into
+// the page, so only enable it for trusted audiences. Defaults to false.
+func QueryConfigEnabled(enabled bool) func(*Config) {
+	return func(c *Config) {
+		c.QueryConfigEnabled = enabled
+	}
+}
+
+// DisplayRequestDuration shows the duration of "Try it out" requests.
+// Defaults to false.
+func DisplayRequestDuration(display bool) func(*Config) {
+	return func(c *Config) {
+		c.DisplayRequestDuration = display
+	}
+}
+
+// RequestSnippetsEnabled shows request code snippets.
+// Defaults to false.
+func RequestSnippetsEnabled(enabled bool) func(*Config) {
+	return func(c *Config) {
+		c.RequestSnippetsEnabled = enabled
+	}
+}
+
+// RequestSnippets configures the request code snippet generators and languages.
+func RequestSnippets(fn func(*RequestSnippetsConfig)) func(*Config) {
+	return func(c *Config) {
+		if c.RequestSnippets == nil {
+			c.RequestSnippets = &RequestSnippetsConfig{DefaultExpanded: true}
+		}
+		fn(c.RequestSnippets)
+	}
+}
+
+// WithCredentials sends cookies and other credentials with "Try it out" requests.
+// Defaults to false.
+func WithCredentials(withCredentials bool) func(*Config) {
+	return func(c *Config) {
+		c.WithCredentials = withCredentials
+	}
+}
+
+// SupportedSubmitMethods sets the HTTP methods "Try it out" is enabled for, e.g. "get", "post".
+// Calling it without methods disables request execution for all operations.
+func SupportedSubmitMethods(methods ...string) func(*Config) {
+	return func(c *Config) {
+		vs := make([]string, len(methods))
+		for i, v := range methods {
+			vs[i] = strings.ToLower(v)
+		}
+		c.SupportedSubmitMethods = vs
+	}
+}
+
+// SubmitMethodRules sets the rules enabling or disabling "Try it out" per operation, e.g. to only
+// allow POST requests on the operations tagged "staging". They are enforced by a plugin wrapping the
+// operations, the first rule matching an operation applies and the others fall back to
+// SupportedSubmitMethods.
+func SubmitMethodRules(rules ...SubmitMethodRule) func(*Config) {
+	return func(c *Config) {
+		c.SubmitMethodRules = rules
+	}
+}
+
+// AllowedOrigins sets the origins allowed to fetch the API definition cross-origin,
+// e.g. "https://editor.swagger.io". "*" allows any origin.
+func AllowedOrigins(origins ...string) func(*Config) {
+	return func(c *Config) {
+		c.AllowedOrigins = origins
+	}
+}
+
+// SpecFormat json, yaml.
+func SpecFormat(format string) func(*Config) {
+	return func(c *Config) {
+		c.SpecFormat = format
+	}
+}
+
+// SpecPath sets the path below the handler the API definition is served at, e.g. "openapi.json".
+// The UI requests it from there unless URL is set too.
+func SpecPath(path string) func(*Config) {
+	return func(c *Config) {
+		c.SpecPath = path
+	}
+}
+
+// BeforeScript holds JavaScript to be run right before the Swagger UI object is created.
+func BeforeScript(js string) func(*Config) {
+	return func(c *Config) {
+		c.BeforeScript = template.JS(js)
+	}
+}
+
+// AfterScript holds JavaScript to be run right after the Swagger UI object is created
+// and set on the window.
+func AfterScript(js string) func(*Config) {
+	return func(c *Config) {
+		c.AfterScript = template.JS(js)
+	}
+}
+
+func newConfig(configFns ...func(*Config)) *Config {
+	config := Config{
+		URL:                   "doc.json",
+		DocExpansion:          "list",
+		DomID:                 "swagger-ui",
+		InstanceName:          "swagger",
+		DeepLinking:           true,
+		PersistAuthorization:  false,
+		SpecFormat:            "json",
+		ShowTopBar:            true,
+		ShowModels:            true,
+		ShowDownloadButton:    true,
+		Logger:                nopLogger{},
+		ValidatorURL:          new(string),
+		ShowMutatedRequest:    true,
+		Renderer:              "swagger",
+		RedocBundleURL:        defaultRedocBundleURL,
+		ContentSecurityPolicy: defaultContentSecurityPolicy,
+	}
+
+	for _, fn := range configFns {
+		fn(&config)
+	}
+
+	if config.Theme == "" {
+		config.Theme = "light"
+	}
+
+	if config.Renderer == "" {
+		config.Renderer = "swagger"
+	}
+
+	if config.RedocBundleURL == "" {
+		config.RedocBundleURL = defaultRedocBundleURL
+	}
+
+	if config.ContentSecurityPolicy == "" {
+		config.ContentSecurityPolicy = defaultContentSecurityPolicy
+	}
+
+	if config.InstanceName == "" {
+		config.InstanceName = swag.Name
+	}
+
+	if config.SpecFormat == "" {
+		config.SpecFormat = "json"
+	}
+
+	config.SpecPath = strings.TrimPrefix(config.SpecPath, "/")
+	if config.SpecPath == "" {
+		config.SpecPath = "doc." + config.SpecFormat
+	}
+
+	if config.URL == "doc.json" {
+		config.URL = config.SpecPath
+	}
+
+	if config.Layout == "" {
+		config.Layout = "BaseLayout"
+		if len(config.URLs) > 0 || len(config.Instances) > 0 {
+			config.Layout = "StandaloneLayout"
+		}
+	}
+
+	if !config.ShowTopBar {
+		config.Layout = "BaseLayout"
+	}
+
+	if !config.ShowModels {
+		depth := -1
+		config.DefaultModelsExpandDepth = &depth
+	}
+
+	return &config
+}
+
+// enumOption is a configuration value restricted to a set of allowed values.
+type enumOption struct {
+	name    string
+	value   *string
+	def     string
+	allowed []string
+}
+
+// validate reports an error for configuration values Swagger UI does not support.
+// Unless StrictValidation is set, such values are reset to their defaults and logged instead.
+func (c *Config) validate() error {
+	layout := "BaseLayout"
+	if len(c.URLs) > 0 || len(c.Instances) > 0 {
+		layout = "StandaloneLayout"
+	}
+
+	options := []enumOption{
+		{name: "doc expansion", value: &c.DocExpansion, def: "list", allowed: []string{"list", "full", "none"}},
+		{name: "default model rendering", value: &c.DefaultModelRendering, allowed: []string{"", "example", "model"}},
+		{name: "layout", value: &c.Layout, def: layout, allowed: []string{"StandaloneLayout", "BaseLayout"}},
+		{name: "renderer", value: &c.Renderer, def: "swagger", allowed: []string{"swagger", "redoc"}},
+		{name: "theme", value: &c.Theme, def: "light", allowed: []string{"light", "dark", "auto"}},
+	}
+
+	if c.SyntaxHighlight != nil {
+		options = append(options, enumOption{
+			name:    "syntax highlight theme",
+			value:   &c.SyntaxHighlight.Theme,
+			allowed: []string{"", "agate", "arta", "monokai", "nord", "obsidian", "tomorrow-night"},
+		})
+	}
+
+	for _, opt := range options {
+		if contains(opt.allowed, *opt.value) {
+			continue
+		}
+
+		if c.StrictValidation {
+			return fmt.Errorf("httpSwagger: invalid %s %q", opt.name, *opt.value)
+		}
+
+		c.warnf("httpSwagger: invalid %s %q, using %q", opt.name, *opt.value, opt.def)
+		*opt.value = opt.def
+	}
+
+	keys := make([]string, 0, len(c.UIConfig))
+	for k := range c.UIConfig {
+		keys = append(keys, string(k))
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		name := strings.Trim(k, `"'`)
+		if strings.HasPrefix(name, "x-") || contains(uiConfigKeys, name) {
+			continue
+		}
+
+		if c.StrictValidation {
+			return fmt.Errorf("httpSwagger: unknown UIConfig key %q", k)
+		}
+
+		c.warnf("httpSwagger: unknown UIConfig key %q", k)
+	}
+
+	return nil
+}
+
+// uiConfigKeys are the Swagger UI configuration options, see
+// https://swagger.io/docs/open-source-tools/swagger-ui/usage/configuration/.
+var uiConfigKeys = []string{
+	"configUrl", "dom_id", "domNode", "spec", "url", "urls", "urls.primaryName", "queryConfigEnabled",
+	"layout", "plugins", "presets", "deepLinking", "displayOperationId", "defaultModelsExpandDepth",
+	"defaultModelExpandDepth", "defaultModelRendering", "displayRequestDuration", "docExpansion", "filter",
+	"maxDisplayedTags", "operationsSorter", "showExtensions", "showCommonExtensions", "tagsSorter",
+	"useUnsafeMarkdown", "onComplete", "syntaxHighlight", "tryItOutEnabled", "requestSnippetsEnabled",
+	"requestSnippets", "oauth2RedirectUrl", "requestInterceptor", "request.curlOptions",
+	"responseInterceptor", "showMutatedRequest", "supportedSubmitMethods", "validatorUrl",
+	"withCredentials", "modelPropertyMacro", "parameterMacro", "persistAuthorization",
+}
+
+// warnf reports a configuration problem to the configured Logger, or to the standard logger without one.
+func (c *Config) warnf(format string, args ...interface{}) {
+	if _, ok := c.Logger.(nopLogger); c.Logger != nil && !ok {
+		c.Logger.Printf(format, args...)
+
+		return
+	}
+
+	log.Printf(format, args...)
+}
+
+// warnUnsupportedSpec warns when an API definition is an OpenAPI 3.1 one, which the embedded
+// Swagger UI does not render. Definitions of a SpecProvider are only known at request time.
+func (c *Config) warnUnsupportedSpec() {
+	if c.Renderer != "swagger" || c.CDNBaseURL != "" || c.AssetFS != nil || c.Template != nil || c.StaticRender {
+		return
+	}
+
+	var docs [][]byte
+
+	switch {
+	case c.SpecProvider != nil:
+		return
+	case c.SpecFS != nil:
+		if doc, err := fs.ReadFile(c.SpecFS, c.SpecFSPath); err == nil {
+			docs = append(docs, doc)
+		}
+	default:
+		for _, name := range append([]string{c.InstanceName}, c.Instances...) {
+			if doc, err := swag.ReadDoc(name); err == nil {
+				docs = append(docs, []byte(doc))
+			}
+		}
+	}
+
+	for _, doc := range docs {
+		spec, err := decodeSpec(doc)
+		if err != nil {
+			continue
+		}
+
+		if version := str(spec["openapi"]); strings.HasPrefix(version, "3.1") {
+			c.warnf("httpSwagger: the API definition is OpenAPI %s, which Swagger UI %s does not support, "+
+				"load a 5.x release with CDN", version, SwaggerUIVersion)
+
+			return
+		}
+	}
+}
+
+// logf reports an internal error to the configured Logger.
+func (c *Config) logf(format string, args ...interface{}) {
+	if c.Logger != nil {
+		c.Logger.Printf(format, args...)
+	}
+}
+
+// event reports a request event to the configured MetricsHook.
+func (c *Config) event(name string) {
+	if c.MetricsHook != nil {
+		c.MetricsHook(name)
+	}
+}
+
+// authorized reports whether r carries the expected credentials, in constant time.
+func (b *BasicAuthConfig) authorized(r *http.Request) bool {
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+
+	// comparing digests keeps the comparison time independent of the credentials length
+	givenUser, givenPass := sha256.Sum256([]byte(username)), sha256.Sum256([]byte(password))
+	wantUser, wantPass := sha256.Sum256([]byte(b.Username)), sha256.Sum256([]byte(b.Password))
+
+	return subtle.ConstantTimeCompare(givenUser[:], wantUser[:])&subtle.ConstantTimeCompare(givenPass[:], wantPass[:]) == 1
+}
+
+// requestURI returns the uri the resource served is resolved from. It is the unmodified request uri
+// for server requests, so the handler works the same whether it is mounted on a sub-path or behind
+// http.StripPrefix, and the request url for requests built by hand, e.g. by serverless adapters.
+func requestURI(r *http.Request) string {
+	// requests sent to a proxy carry an absolute uri
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil && u.IsAbs() {
+		return u.RequestURI()
+	}
+
+	if r.RequestURI != "" {
+		return r.RequestURI
+	}
+
+	return r.URL.RequestURI()
+}
+
+// acceptedSpecFormat returns the API definition format requested by the Accept header of r.
+func acceptedSpecFormat(r *http.Request) string {
+	for _, v := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case "application/x-yaml", "application/yaml", "text/yaml":
+			return "yaml"
+		}
+	}
+
+	return "json"
+}
+
+// splitQuery splits a request uri into its path and its query, including the leading `?`.
+func splitQuery(uri string) (string, string) {
+	if i := strings.IndexByte(uri, '?'); i >= 0 {
+		return uri[:i], uri[i:]
+	}
+
+	return uri, ""
+}
+
+// allowsOrigin reports whether the API definition may be fetched from origin.
+func (c *Config) allowsOrigin(origin string) bool {
+	return contains(c.AllowedOrigins, "*") || contains(c.AllowedOrigins, origin)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handler wraps `http.Handler` into `http.HandlerFunc`.
+// It panics if the configuration is invalid and StrictValidation is set.
+func Handler(configFns ...func(*Config)) http.HandlerFunc {
+	return NewHandler(configFns...).ServeHTTP
+}
+
+// NewHandler returns a SwaggerHandler serving Swagger UI and the API definition.
+// It panics if the configuration is invalid and StrictValidation is set.
+func NewHandler(configFns ...func(*Config)) *SwaggerHandler {
+	config := newConfig(configFns...)
+
+	if err := config.validate(); err != nil {
+		panic(err)
+	}
+
+	config.warnUnsupportedSpec()
+
+	return newSwaggerHandler(config)
+}
+
+// HandlerWithError wraps `http.Handler` into `http.HandlerFunc` like Handler, but
+// returns an error if the configuration is invalid and StrictValidation is set, or if no
+// swagger document is registered under the configured InstanceName.
+func HandlerWithError(configFns ...func(*Config)) (http.HandlerFunc, error) {
+	config := newConfig(configFns...)
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	config.warnUnsupportedSpec()
+
+	names := config.Instances
+	if config.SpecProvider == nil && config.SpecFS == nil {
+		names = append([]string{config.InstanceName}, names...)
+	}
+
+	for _, name := range names {
+		if _, err := swag.ReadDoc(name); err != nil {
+			return nil, fmt.Errorf("httpSwagger: instance %q: %w", name, err)
+		}
+	}
+
+	return newSwaggerHandler(config).ServeHTTP, nil
+}
+
+// maxCachedHandlers bounds the handlers cached by HandlerFromRequest, which are all dropped once
+// it is reached.
+const maxCachedHandlers = 256
+
+// HandlerFromRequest wraps `http.Handler` into `http.HandlerFunc`, computing the configuration
+// for each request from the config functions returned by fn, e.g. to vary the API definition url
+// per tenant. Requests whose configuration is invalid, with StrictValidation set, fail with 500.
+// The handlers are cached by configuration, except the configurations holding functions, e.g.
+// a SpecProvider, which cannot be compared and get a new handler for each request.
+func HandlerFromRequest(fn func(*http.Request) []func(*Config)) http.HandlerFunc {
+	var (
+		mu       sync.Mutex
+		handlers = map[[sha256.Size]byte]*SwaggerHandler{}
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		config := newConfig(fn(r)...)
+
+		key, cacheable := configKey(config)
+		if cacheable {
+			mu.Lock()
+			h := handlers[key]
+			mu.Unlock()
+
+			if h != nil {
+				h.ServeHTTP(w, r)
+
+				return
+			}
+		}
+
+		if err := config.validate(); err != nil {
+			config.logf("%v", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return
+		}
+
+		h := newSwaggerHandler(config)
+
+		if cacheable {
+			mu.Lock()
+			if len(handlers) >= maxCachedHandlers {
+				handlers = map[[sha256.Size]byte]*SwaggerHandler{}
+			}
+			handlers[key] = h
+			mu.Unlock()
+		}
+
+		h.ServeHTTP(w, r)
+	}
+}
+
+// configKey returns the hash of the configuration, or false if it holds functions.
+func configKey(config *Config) ([sha256.Size]byte, bool) {
+	var key [sha256.Size]byte
+
+	sum := sha256.New()
+	if !hashValue(sum, reflect.ValueOf(*config)) {
+		return key, false
+	}
+
+	copy(key[:], sum.Sum(nil))
+
+	return key, true
+}
+
+// hashValue writes an encoding of v to w, reporting false if v holds a non-nil function.
+// Templates and the pointers held by interfaces, e.g. a Logger, are encoded by address.
+func hashValue(w io.Writer, v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Func:
+		fmt.Fprint(w, "func;")
+
+		return v.IsNil()
+	case reflect.Interface:
+		if v.IsNil() {
+			fmt.Fprint(w, "nil;")
+
+			return true
+		}
+
+		fmt.Fprintf(w, "%s(", v.Elem().Type())
+		if v.Elem().Kind() == reflect.Ptr {
+			fmt.Fprintf(w, "%#x);", v.Elem().Pointer())
+
+			return true
+		}
+
+		if !hashValue(w, v.Elem()) {
+			return false
+		}
+
+		fmt.Fprint(w, ");")
+
+		return true
+	case reflect.Ptr:
+		if v.IsNil() {
+			fmt.Fprint(w, "nil;")
+
+			return true
+		}
+
+		if v.Type() == reflect.TypeOf((*template.Template)(nil)) {
+			fmt.Fprintf(w, "%#x;", v.Pointer())
+
+			return true
+		}
+
+		fmt.Fprint(w, "&")
+
+		return hashValue(w, v.Elem())
+	case reflect.Struct:
+		fmt.Fprint(w, "{")
+		for i := 0; i < v.NumField(); i++ {
+			fmt.Fprintf(w, "%s:", v.Type().Field(i).Name)
+			if !hashValue(w, v.Field(i)) {
+				return false
+			}
+		}
+		fmt.Fprint(w, "};")
+
+		return true
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			fmt.Fprint(w, "nil;")
+
+			return true
+		}
+
+		fmt.Fprintf(w, "[%d:", v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if !hashValue(w, v.Index(i)) {
+				return false
+			}
+		}
+		fmt.Fprint(w, "];")
+
+		return true
+	case reflect.Map:
+		if v.IsNil() {
+			fmt.Fprint(w, "nil;")
+
+			return true
+		}
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprintf("%#v", keys[i]) < fmt.Sprintf("%#v", keys[j])
+		})
+
+		fmt.Fprintf(w, "map[%d:", len(keys))
+		for _, k := range keys {
+			if !hashValue(w, k) || !hashValue(w, v.MapIndex(k)) {
+				return false
+			}
+		}
+		fmt.Fprint(w, "];")
+
+		return true
+	default:
+		fmt.Fprintf(w, "%#v;", v)
+
+		return true
+	}
+}
+
+// RegisterRoutes registers the handler on mux under the path pattern, e.g. "/swagger/", or with
+// the method-aware patterns of Go 1.22, e.g. "GET /swagger/". The pattern path without trailing
+// slash redirects to it, which redirects to the index page.
+func RegisterRoutes(mux *http.ServeMux, pattern string, configFns ...func(*Config)) {
+	method, p := "", pattern
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		method, p = pattern[:i+1], strings.TrimLeft(pattern[i+1:], " ")
+	}
+
+	h := Handler(configFns...)
+
+	p = strings.TrimSuffix(p, "/")
+	mux.Handle(method+p+"/", h)
+
+	if p != "" {
+		mux.Handle(method+p, http.StripPrefix(p, h))
+	}
+}
+
+// MultiHandler serves the swagger documents registered under each of the given instance names
+// at `{name}/`, each with its own configuration, and an index linking to them at the mount root.
+func MultiHandler(instances map[string]func(*Config)) http.HandlerFunc {
+	names := make([]string, 0, len(instances))
+	handlers := make(map[string]http.HandlerFunc, len(instances))
+
+	for name, fn := range instances {
+		configFns := []func(*Config){InstanceName(name)}
+		if fn != nil {
+			configFns = append(configFns, fn)
+		}
+
+		names = append(names, name)
+		handlers[name] = Handler(configFns...)
+	}
+
+	sort.Strings(names)
+
+	index, _ := template.New("instances_index.html").Parse(instancesIndexTempl)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		dir, file := path.Split(r.URL.Path)
+
+		if h, ok := handlers[path.Base(dir)]; ok {
+			h(w, r)
+
+			return
+		}
+
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		if _, ok := handlers[file]; ok {
+			http.Redirect(w, r, file+"/", http.StatusMovedPermanently)
+
+			return
+		}
+
+		if file != "" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = index.Execute(w, names)
+	}
+}
+
+// SwaggerHandler serves Swagger UI and the API definition.
+type SwaggerHandler struct {
+	config *Config
+	index  *template.Template
+
+	// the index page rendered once for requests it does not depend on
+	pageOnce sync.Once
+	page     []byte
+	pageErr  error
+
+	// the indented API definitions by instance, along with the document they were indented from
+	prettyMu sync.Mutex
+	pretty   map[string][2][]byte
+}
+
+var requestURIRe = regexp.MustCompile(`^(.*/)([^?].*)?[?|.]*$`)
+
+// templateFuncs are the functions of the built-in index page template.
+var templateFuncs = template.FuncMap{
+	// js writes v as JSON, without the spaces html/template pads JavaScript values with
+	"js": func(v interface{}) (template.JS, error) {
+		b, err := json.Marshal(v)
+
+		return template.JS(b), err
+	},
+}
+
+// create a template with name
+var (
+	indexTemplate = template.Must(template.New("swagger_index.html").Funcs(templateFuncs).Parse(indexTempl + compactConfigTempl))
+	redocTemplate = template.Must(template.New("redoc_index.html").Parse(redocTempl))
+)
+
+const (
+	defaultRedocBundleURL        = "https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"
+	offlineRedocBundleURL        = "./redoc.standalone.js"
+	basicAuthChallenge           = `Basic realm="Swagger UI", charset="UTF-8"`
+	defaultContentSecurityPolicy = "script-src 'nonce-{nonce}'"
+)
+
+func newSwaggerHandler(config *Config) *SwaggerHandler {
+	return &SwaggerHandler{
+		config: config,
+		index:  pageTemplate(config),
+	}
+}
+
+// pageTemplate returns the index page template of the configured renderer.
+func pageTemplate(config *Config) *template.Template {
+	if config.Template != nil {
+		return config.Template
+	}
+
+	if config.Renderer == "redoc" {
+		return redocTemplate
+	}
+
+	return indexTemplate
+}
+
+// Config returns the resolved configuration of the handler. It must not be changed once
+// the handler has served the index page, which is rendered once and cached.
+func (h *SwaggerHandler) Config() *Config {
+	return h.config
+}
+
+// ServeHTTP implements http.Handler.
+func (h *SwaggerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	config := h.config
+
+	uri := requestURI(r)
+
+	if config.RequireHTTPS && !isHTTPS(r, config.TrustForwardedHeaders) {
+		host, prefix := r.Host, ""
+		if config.TrustForwardedHeaders {
+			if forwardedHost := forwardedHeader(r, "X-Forwarded-Host"); forwardedHost != "" {
+				host = forwardedHost
+			}
+			prefix = strings.TrimSuffix(forwardedHeader(r, "X-Forwarded-Prefix"), "/")
+		}
+
+		http.Redirect(w, r, "https://"+host+prefix+uri, http.StatusPermanentRedirect)
+
+		return
+	}
+
+	matches := requestURIRe.FindStringSubmatch(uri)
+	if matches == nil {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	path := matches[2]
+
+	// the swag documents are served in both formats, e.g. at doc.json and doc.yaml
+	if config.SpecProvider == nil && config.SpecFS == nil && path != config.SpecPath {
+		format := strings.TrimPrefix(filepath.Ext(path), ".")
+		stem := strings.TrimSuffix(config.SpecPath, filepath.Ext(config.SpecPath))
+
+		if (format == "json" || format == "yaml") && path == stem+"."+format {
+			formatConfig := *config
+			formatConfig.SpecFormat, formatConfig.SpecPath = format, path
+			config = &formatConfig
+		}
+	}
+
+	if config.NegotiateSpecFormat && config.SpecProvider == nil && config.SpecFS == nil && path == config.SpecPath {
+		w.Header().Add("Vary", "Accept")
+
+		if format := acceptedSpecFormat(r); format != config.SpecFormat {
+			formatConfig := *config
+			formatConfig.SpecFormat = format
+			config = &formatConfig
+		}
+	}
+
+	// the API definitions of additional instances are served one directory below the handler
+	if path == config.SpecPath && len(config.Instances) > 0 {
+		dir := strings.TrimSuffix(matches[1], "/")
+		if name := dir[strings.LastIndex(dir, "/")+1:]; contains(config.Instances, name) {
+			instanceConfig := *config
+			instanceConfig.InstanceName = name
+			instanceConfig.SpecProvider, instanceConfig.SpecFS = nil, nil
+			config = &instanceConfig
+		}
+	}
+
+	// the API definition answers CORS preflight requests when cross-origin access is configured
+	cors := path == config.SpecPath && len(config.AllowedOrigins) > 0
+
+	// preflight requests never carry credentials
+	preflight := cors && r.Method == http.MethodOptions
+
+	if config.BasicAuth != nil && !preflight && !config.BasicAuth.authorized(r) {
+		w.Header().Set("WWW-Authenticate", basicAuthChallenge)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+		return
+	}
+
+	if config.Authorize != nil && !preflight {
+		if err := config.Authorize(r); err != nil {
+			// 401 requires a challenge, without one the request is forbidden
+			status := http.StatusForbidden
+
+			var unauthorized *unauthorizedError
+			if errors.As(err, &unauthorized) {
+				w.Header().Set("WWW-Authenticate", unauthorized.challenge)
+				status = http.StatusUnauthorized
+			} else if errors.Is(err, ErrUnauthorized) && config.BasicAuth != nil {
+				w.Header().Set("WWW-Authenticate", basicAuthChallenge)
+				status = http.StatusUnauthorized
+			}
+			http.Error(w, http.StatusText(status), status)
+
+			return
+		}
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && !preflight {
+		if cors {
+			w.Header().Set("Allow", "GET, HEAD, OPTIONS")
+		} else {
+			w.Header().Set("Allow", "GET, HEAD")
+		}
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	// assets resolve against the mount path only when it ends with a slash. The bare mount path
+	// is recognized when http.StripPrefix left nothing of it, or when it is the configured BasePath.
+	if uriPath, query := splitQuery(uri); !strings.HasSuffix(uriPath, "/") &&
+		(r.URL.Path == "" || uriPath == strings.TrimSuffix(config.BasePath, "/")) {
+		http.Redirect(w, r, uriPath+"/"+query, http.StatusMovedPermanently)
+
+		return
+	}
+
+	switch filepath.Ext(path) {
+	case ".html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	case ".css":
+		w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	case ".js":
+		w.Header().Set("Content-Type", "application/javascript")
+	case ".png":
+		w.Header().Set("Content-Type", "image/png")
+	case ".json":
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	case ".yaml":
+		w.Header().Set("Content-Type", "application/x-yaml; charset=utf-8")
+	}
+
+	basePath := config.BasePath
+	if basePath == "" && config.TrustForwardedHeaders {
+		basePath = forwardedBasePath(r, matches[1])
+	}
+
+	if config.HealthPath != "" && path == strings.TrimPrefix(config.HealthPath, "/") {
+		serveHealth(w, r, config)
+
+		return
+	}
+
+	if config.ExposeConfigEndpoint && path == "config.json" && path != config.SpecPath {
+		serveConfig(w, r, config)
+
+		return
+	}
+
+	switch path {
+	case "index.html":
+		config.event("ui")
+
+		if config.NoIndex {
+			w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+		}
+
+		data := newTemplateData(config, basePath)
+		if config.CSPNonceFunc != nil {
+			data.Nonce = config.CSPNonceFunc(r)
+			w.Header().Set("Content-Security-Policy", strings.ReplaceAll(config.ContentSecurityPolicy, "{nonce}", data.Nonce))
+		}
+
+		var (
+			page []byte
+			err  error
+		)
+
+		// the page only depends on the request through the nonce and forwarded headers, unless it
+		// holds the API definition, which may change between requests
+		if !config.StaticRender && !config.InlineSpec && data.Nonce == "" && basePath == config.BasePath {
+			h.pageOnce.Do(func() {
+				h.page, h.pageErr = renderPage(r.Context(), h.index, data)
+			})
+			page, err = h.page, h.pageErr
+		} else {
+			page, err = renderPage(r.Context(), h.index, data)
+		}
+
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+
+		if err != nil {
+			config.logf("httpSwagger: rendering index page: %v", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return
+		}
+
+		writeBody(w, r, page)
+	case config.SpecPath:
+		if cors {
+			w.Header().Add("Vary", "Origin")
+
+			if origin := r.Header.Get("Origin"); origin != "" && config.allowsOrigin(origin) {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+
+			if r.Method == http.MethodOptions {
+				w.Header().Del("Content-Type")
+				w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+				if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+					w.Header().Set("Access-Control-Allow-Headers", headers)
+				}
+				w.WriteHeader(http.StatusNoContent)
+
+				return
+			}
+		}
+
+		config.event("spec")
+
+		if modTime := specModTime(config); !modTime.IsZero() {
+			w.Header().Set("Last-Modified", modTime.UTC().Format(http.TimeFormat))
+
+			if notModified(r, modTime) {
+				w.Header().Del("Content-Type")
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+		}
+
+		doc, contentType, err := readDoc(r.Context(), config)
+		if errors.Is(err, context.Canceled) {
+			// the client went away, there is no one to answer
+			return
+		}
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			specError(w, http.StatusGatewayTimeout, "timed out reading the API definition")
+
+			return
+		}
+
+		if err != nil {
+			config.logf("httpSwagger: reading API definition: %v", err)
+
+			msg := "API definition unavailable"
+			if config.SpecProvider == nil && config.SpecFS == nil {
+				msg = "swagger doc not found for instance " + config.InstanceName
+			}
+
+			specError(w, http.StatusInternalServerError, msg)
+
+			return
+		}
+
+		if len(config.OverrideSpecServers) > 0 {
+			if doc, err = overrideServers(doc, contentType, specServers(r, config)); err != nil {
+				config.logf("httpSwagger: overriding API definition servers: %v", err)
+				specError(w, http.StatusInternalServerError, "invalid API definition")
+
+				return
+			}
+		}
+
+		if len(config.ServerVariables) > 0 {
+			if doc, err = setServerVariables(doc, contentType, config.ServerVariables); err != nil {
+				config.logf("httpSwagger: setting API definition server variables: %v", err)
+				specError(w, http.StatusInternalServerError, "invalid API definition")
+
+				return
+			}
+		}
+
+		if doc, err = config.transformSpec(r.Context(), doc, contentType); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+
+			config.logf("%v", err)
+			specError(w, http.StatusInternalServerError, "API definition unavailable")
+
+			return
+		}
+
+		if config.PrettyJSON && strings.HasPrefix(contentType, "application/json") {
+			doc = h.indent(config.InstanceName, doc)
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if config.DevReload {
+			// weak since it does not depend on the content coding
+			sum := sha256.Sum256(doc)
+			w.Header().Set("ETag", `W/"`+hex.EncodeToString(sum[:16])+`"`)
+		}
+
+		if acceptsEncoding(r, "gzip") {
+			var buf bytes.Buffer
+
+			zw := gzip.NewWriter(&buf)
+			_, _ = zw.Write(doc)
+			_ = zw.Close()
+
+			w.Header().Set("Content-Encoding", "gzip")
+			doc = buf.Bytes()
+		}
+
+		writeBody(w, r, doc)
+	case "":
+		if basePath != "" {
+			http.Redirect(w, r, strings.TrimSuffix(basePath, "/")+"/index.html", http.StatusMovedPermanently)
+
+			return
+		}
+
+		http.Redirect(w, r, matches[1]+"index.html", http.StatusMovedPermanently)
+	default:
+		// the OAuth2 redirect page must be served from the origin of the UI
+		if config.CDNBaseURL != "" && path != "oauth2-redirect.html" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		if config.AssetFS != nil {
+			config.event("asset")
+
+			if config.CacheControl != "" {
+				w.Header().Set("Cache-Control", config.CacheControl)
+			}
+
+			r = r.Clone(r.Context())
+			r.URL.Path, r.URL.RawPath = "/"+path, ""
+			http.FileServer(http.FS(config.AssetFS)).ServeHTTP(w, r)
+
+			return
+		}
+
+		if config.AssetOverrides != nil && serveOverride(w, r, config, path) {
+			return
+		}
+
+		if a, ok := assets[path]; ok {
+			config.event("asset")
+
+			if config.CacheControl != "" {
+				w.Header().Set("Cache-Control", config.CacheControl)
+			}
+
+			a.ServeHTTP(w, r)
+
+			return
+		}
+
+		http.NotFound(w, r)
+	}
+}
+
+// indent returns doc indented, reusing the result of the previous call for the same instance
+// as long as the document did not change.
+func (h *SwaggerHandler) indent(instance string, doc []byte) []byte {
+	h.prettyMu.Lock()
+	defer h.prettyMu.Unlock()
+
+	if cached, ok := h.pretty[instance]; ok && bytes.Equal(cached[0], doc) {
+		return cached[1]
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, doc, "", "  "); err != nil {
+		return doc
+	}
+
+	if h.pretty == nil {
+		h.pretty = make(map[string][2][]byte)
+	}
+	h.pretty[instance] = [2][]byte{doc, buf.Bytes()}
+
+	return buf.Bytes()
+}
+
+// serveHealth answers whether the API definition can be read.
+func serveHealth(w http.ResponseWriter, r *http.Request, config *Config) {
+	status, body := http.StatusOK, map[string]string{"status": "ok"}
+
+	if _, _, err := readDoc(r.Context(), config); err != nil {
+		// the error may tell about the internals of the API definition source
+		config.logf("httpSwagger: health check: %v", err)
+
+		status, body = http.StatusServiceUnavailable, map[string]string{"status": "unavailable"}
+	}
+
+	b, _ := json.Marshal(body)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(b)
+	}
+}
+
+// serveConfig replies with the configuration encoded to JSON by field name.
+func serveConfig(w http.ResponseWriter, r *http.Request, config *Config) {
+	v := reflect.ValueOf(config).Elem()
+
+	fields := make(map[string]interface{}, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		// functions and interfaces, e.g. the Logger or the AssetFS, cannot be encoded
+		if kind := v.Field(i).Kind(); kind == reflect.Func || kind == reflect.Interface {
+			continue
+		}
+
+		fields[v.Type().Field(i).Name] = v.Field(i).Interface()
+	}
+
+	delete(fields, "Template")
+
+	if config.BasicAuth != nil {
+		fields["BasicAuth"] = map[string]string{"Username": config.BasicAuth.Username}
+	}
+
+	// the query parameters sent to the authorization server may hold credentials too
+	if config.OAuth2Config != nil {
+		oauth2 := *config.OAuth2Config
+		oauth2.ClientSecret, oauth2.AdditionalQueryStringParams = "", nil
+		fields["OAuth2Config"] = oauth2
+	}
+
+	b, err := marshalJSON(fields)
+	if err != nil {
+		config.logf("httpSwagger: encoding configuration: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	writeBody(w, r, b)
+}
+
+// writeBody writes body with its Content-Length, omitting the body itself for HEAD requests.
+func writeBody(w http.ResponseWriter, r *http.Request, body []byte) {
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	_, _ = w.Write(body)
+}
+
+// specError replies to an API definition request with a JSON error object.
+func specError(w http.ResponseWriter, code int, msg string) {
+	b, _ := json.Marshal(map[string]string{"error": msg})
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(code)
+	_, _ = w.Write(b)
+}
+
+// RenderHTML returns the index page served by the handler of the given config functions, e.g. to
+// write it to a file. It returns an error if the configuration is invalid and StrictValidation is set.
+func RenderHTML(configFns ...func(*Config)) ([]byte, error) {
+	config := newConfig(configFns...)
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return renderPage(context.Background(), pageTemplate(config), newTemplateData(config, ""))
+}
+
+// renderPage renders the index page with data: the static reference page with StaticRender or the
+// index template, inlining the API definition with InlineSpec.
+func renderPage(ctx context.Context, index *template.Template, data TemplateData) ([]byte, error) {
+	if data.StaticRender {
+		doc, contentType, err := readDoc(ctx, data.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		if doc, err = data.transformSpec(ctx, doc, contentType); err != nil {
+			return nil, err
+		}
+
+		return renderStatic(data, doc)
+	}
+
+	if data.InlineSpec {
+		spec, err := inlineSpec(ctx, data.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		data.Spec = spec
+	}
+
+	return renderIndex(index, data)
+}
+
+// inlineSpec returns the API definition inlined in the page, converted to JSON.
+func inlineSpec(ctx context.Context, config *Config) (json.RawMessage, error) {
+	doc, contentType, err := readDoc(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc, err = config.transformSpec(ctx, doc, contentType); err != nil {
+		return nil, err
+	}
+
+	if json.Valid(doc) {
+		return doc, nil
+	}
+
+	spec, err := decodeSpec(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(spec)
+}
+
+func renderIndex(index *template.Template, data TemplateData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := index.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// TemplateData is the data the index page template is executed with, see Template.
+// The Config fields are promoted, DomID and URLs excepted.
+type TemplateData struct {
+	*Config
+	// The CSP nonce of the request, empty without CSPNonceFunc.
+	Nonce string
+	// The prefix of the embedded asset urls.
+	AssetsPrefix string
+	// The url of the API definition.
+	DocURL string
+	// The url of the OAuth2 redirect page. Empty means it is derived from the current page.
+	RedirectURL string
+	// The validatorUrl value, `null` when disabled or empty when omitted.
+	Validator template.JS
+	// The supportedSubmitMethods value, empty when omitted.
+	SubmitMethods template.JS
+	// The id of the element Swagger UI is mounted in, without the leading `#`.
+	DomID string
+	// The subresource integrity metadata of the assets by file name.
+	Integrity map[string]string
+	// The JSON API definition inlined in the page with InlineSpec.
+	Spec json.RawMessage
+	// The translations of the Locale from LocaleData, empty without any.
+	Translations template.JS
+	// The API definitions listed in the top bar URL switcher, the URLs followed by the Instances.
+	URLs []URLsConfig
+	// The stylesheet of the dark Theme, empty with the light one.
+	ThemeCSS template.CSS
+}
+
+// newTemplateData returns the index page template data, resolving relative asset and API
+// definition urls against basePath when set.
+func newTemplateData(config *Config, basePath string) TemplateData {
+	data := TemplateData{
+		Config:       config,
+		AssetsPrefix: "./",
+		DocURL:       config.URL,
+		RedirectURL:  config.OAuth2RedirectURL,
+		DomID:        strings.TrimPrefix(config.DomID, "#"),
+	}
+
+	if data.DomID == "" {
+		data.DomID = "swagger-ui"
+	}
+
+	data.URLs = config.URLs
+	if len(config.Instances) > 0 {
+		// the URLs of the configuration are left as they are
+		data.URLs = append([]URLsConfig{}, config.URLs...)
+		for _, name := range config.Instances {
+			data.URLs = append(data.URLs, URLsConfig{URL: name + "/" + config.SpecPath, Name: name})
+		}
+	}
+
+	if config.ReadOnly {
+		data.SubmitMethods = "[]"
+	} else if config.SupportedSubmitMethods != nil {
+		b, _ := json.Marshal(config.SupportedSubmitMethods)
+		data.SubmitMethods = template.JS(b)
+	}
+
+	if config.ValidatorURL != nil {
+		data.Validator = "null"
+		if *config.ValidatorURL != "" {
+			b, _ := json.Marshal(*config.ValidatorURL)
+			data.Validator = template.JS(b)
+		}
+	}
+
+	if basePath != "" {
+		data.AssetsPrefix = strings.TrimSuffix(basePath, "/") + "/"
+
+		if u, err := url.Parse(config.URL); err == nil && !u.IsAbs() && u.Host == "" && !strings.HasPrefix(u.Path, "/") {
+			data.DocURL = data.AssetsPrefix + strings.TrimPrefix(config.URL, "./")
+		}
+
+		// the OAuth2 redirect url must be absolute, so it is only derived from absolute base paths
+		if u, err := url.Parse(basePath); err == nil && u.IsAbs() && data.RedirectURL == "" {
+			data.RedirectURL = data.AssetsPrefix + "oauth2-redirect.html"
+		}
+	}
+
+	if config.Theme == "dark" || config.Theme == "auto" {
+		data.ThemeCSS = darkThemeCSS
+	}
+
+	if translations, ok := config.LocaleData[config.Locale]; ok {
+		data.Translations = translations
+	} else if i := strings.IndexAny(config.Locale, "-_"); i > 0 {
+		data.Translations = config.LocaleData[config.Locale[:i]]
+	}
+
+	data.Integrity = config.AssetIntegrity
+	if config.CDNBaseURL != "" {
+		data.AssetsPrefix = strings.TrimSuffix(config.CDNBaseURL, "/") + "/"
+	} else if config.AssetFS == nil && data.Integrity == nil {
+		data.Integrity = make(map[string]string, len(integrityAssets))
+		for _, name := range integrityAssets {
+			if !isOverridden(config, name) {
+				data.Integrity[name] = assets[name].Integrity()
+			}
+		}
+	}
+
+	return data
+}
+
+// isOverridden reports whether the asset name is served from the AssetOverrides.
+func isOverridden(config *Config, name string) bool {
+	if config.AssetOverrides == nil {
+		return false
+	}
+
+	f, err := config.AssetOverrides.Open("/" + name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+
+	return err == nil && !fi.IsDir()
+}
+
+// serveOverride serves the asset name from the AssetOverrides, reporting whether it holds it.
+func serveOverride(w http.ResponseWriter, r *http.Request, config *Config, name string) bool {
+	f, err := config.AssetOverrides.Open("/" + name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		return false
+	}
+
+	config.event("asset")
+
+	if config.CacheControl != "" {
+		w.Header().Set("Cache-Control", config.CacheControl)
+	}
+
+	http.ServeContent(w, r, name, fi.ModTime(), f)
+
+	return true
+}
+
+// integrityAssets are the embedded assets loaded by the index page.
+var integrityAssets = []string{"swagger-ui.css", "swagger-ui-bundle.js", "swagger-ui-standalone-preset.js"}
+
+// forwardedBasePath returns the public path of dir built from the X-Forwarded-Proto,
+// X-Forwarded-Host and X-Forwarded-Prefix request headers, or "" if none is set.
+func forwardedBasePath(r *http.Request, dir string) string {
+	proto := forwardedHeader(r, "X-Forwarded-Proto")
+	host := forwardedHeader(r, "X-Forwarded-Host")
+	prefix := strings.TrimSuffix(forwardedHeader(r, "X-Forwarded-Prefix"), "/")
+
+	if proto == "" && host == "" {
+		if prefix == "" {
+			return ""
+		}
+
+		return prefix + dir
+	}
+
+	if proto == "" {
+		proto = "http"
+		if r.TLS != nil {
+			proto = "https"
+		}
+	}
+
+	if host == "" {
+		host = r.Host
+	}
+
+	return proto + "://" + host + prefix + dir
+}
+
+// isHTTPS reports whether r was made over HTTPS, according to X-Forwarded-Proto when trusted.
+func isHTTPS(r *http.Request, trustForwarded bool) bool {
+	if trustForwarded {
+		if proto := forwardedHeader(r, "X-Forwarded-Proto"); proto != "" {
+			return strings.EqualFold(proto, "https")
+		}
+	}
+
+	return r.TLS != nil
+}
+
+// forwardedHeader returns the first, client-most value of a X-Forwarded-* request header.
+func forwardedHeader(r *http.Request, name string) string {
+	v := r.Header.Get(name)
+	if i := strings.IndexByte(v, ','); i >= 0 {
+		v = v[:i]
+	}
+
+	return strings.TrimSpace(v)
+}
+
+// readDoc returns the API definition and its content type, errEmptyDoc if it is empty.
+func readDoc(ctx context.Context, config *Config) ([]byte, string, error) {
+	doc, contentType, err := readDocSource(ctx, config)
+	if err == nil && len(bytes.TrimSpace(doc)) == 0 {
+		err = errEmptyDoc
+	}
+
+	return doc, contentType, err
+}
+
+// readDocSource returns the API definition and its content type, either from config.SpecProvider,
+// from config.SpecFS or from the swagger document registered under config.InstanceName encoded
+// in config.SpecFormat.
+func readDocSource(ctx context.Context, config *Config) ([]byte, string, error) {
+	if config.SpecProvider != nil {
+		doc, contentType, err := config.SpecProvider(ctx)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, "", ctxErr
+		}
+
+		return doc, contentType, err
+	}
+
+	if config.SpecFS != nil {
+		doc, err := fs.ReadFile(config.SpecFS, config.SpecFSPath)
+
+		return doc, specContentType(config.SpecFSPath), err
+	}
+
+	doc, err := swag.ReadDoc(config.InstanceName)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch config.SpecFormat {
+	case "json":
+		return []byte(doc), "application/json; charset=utf-8", nil
+	case "yaml":
+		b, err := jsonToYAML([]byte(doc))
+
+		return b, "application/x-yaml; charset=utf-8", err
+	default:
+		return nil, "", fmt.Errorf("httpSwagger: unsupported spec format %q", config.SpecFormat)
+	}
+}
+
+// specModTime returns the time the API definition last changed, or the zero time when unknown.
+// Definitions registered in swag are compiled into the binary, so its modification time is used.
+func specModTime(config *Config) time.Time {
+	if config.SpecProvider != nil {
+		return time.Time{}
+	}
+
+	if config.SpecFS != nil {
+		info, err := fs.Stat(config.SpecFS, config.SpecFSPath)
+		if err != nil {
+			return time.Time{}
+		}
+
+		return info.ModTime()
+	}
+
+	return buildTime()
+}
+
+var (
+	buildTimeOnce sync.Once
+	buildTimeVal  time.Time
+)
+
+// buildTime returns the modification time of the running binary, falling back to the time it was
+// first asked for when the binary cannot be found.
+func buildTime() time.Time {
+	buildTimeOnce.Do(func() {
+		buildTimeVal = time.Now()
+
+		name, err := os.Executable()
+		if err != nil {
+			return
+		}
+
+		if info, err := os.Stat(name); err == nil {
+			buildTimeVal = info.ModTime()
+		}
+	})
+
+	return buildTimeVal
+}
+
+// notModified reports whether the If-Modified-Since header of r is not older than modTime.
+func notModified(r *http.Request, modTime time.Time) bool {
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	// the header has a one second resolution
+	return !modTime.Truncate(time.Second).After(since)
+}
+
+// specServers returns the OverrideSpecServers urls, resolving the relative ones against the public
+// url of the request. The absolute ones are kept as they are, they may use server variables.
+func specServers(r *http.Request, config *Config) []string {
+	base := &url.URL{Scheme: "http", Host: r.Host, Path: "/"}
+	if isHTTPS(r, config.TrustForwardedHeaders) {
+		base.Scheme = "https"
+	}
+
+	if config.TrustForwardedHeaders {
+		if host := forwardedHeader(r, "X-Forwarded-Host"); host != "" {
+			base.Host = host
+		}
+
+		base.Path = strings.TrimSuffix(forwardedHeader(r, "X-Forwarded-Prefix"), "/") + "/"
+	}
+
+	servers := make([]string, 0, len(config.OverrideSpecServers))
+	for _, s := range config.OverrideSpecServers {
+		if !strings.Contains(s, "://") {
+			s = base.String() + strings.TrimPrefix(s, "/")
+		}
+
+		servers = append(servers, s)
+	}
+
+	return servers
+}
+
+// editSpec passes the top level fields of the JSON or YAML definition doc with the given keys to fn,
+// decoded to JSON values, and encodes the fields back. The other fields are kept as they are.
+func editSpec(doc []byte, contentType string, keys []string, fn func(fields map[string]interface{})) ([]byte, error) {
+	fields := make(map[string]interface{}, len(keys))
+
+	if strings.Contains(contentType, "json") {
+		var spec map[string]json.RawMessage
+		if err := json.Unmarshal(doc, &spec); err != nil {
+			return nil, err
+		}
+
+		for _, k := range keys {
+			if raw, ok := spec[k]; ok {
+				var v interface{}
+
+				dec := json.NewDecoder(bytes.NewReader(raw))
+				dec.UseNumber()
+				if err := dec.Decode(&v); err != nil {
+					return nil, err
+				}
+
+				fields[k] = v
+			}
+		}
+
+		fn(fields)
+
+		for _, k := range keys {
+			v, ok := fields[k]
+			if !ok {
+				delete(spec, k)
+
+				continue
+			}
+
+			raw, err := marshalJSON(v)
+			if err != nil {
+				return nil, err
+			}
+
+			spec[k] = raw
+		}
+
+		return marshalJSON(spec)
+	}
+
+	var spec yaml.MapSlice
+	if err := yaml.Unmarshal(doc, &spec); err != nil {
+		return nil, err
+	}
+
+	for _, item := range spec {
+		if k, ok := item.Key.(string); ok && contains(keys, k) {
+			b, err := yaml.Marshal(item.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			var v interface{}
+			if err := yaml.Unmarshal(b, &v); err != nil {
+				return nil, err
+			}
+
+			fields[k] = jsonValue(v)
+		}
+	}
+
+	fn(fields)
+
+	edited := make(yaml.MapSlice, 0, len(spec)+len(keys))
+	for _, item := range spec {
+		k, ok := item.Key.(string)
+		if !ok || !contains(keys, k) {
+			edited = append(edited, item)
 
-// URL presents the url pointing to API definition (normally swagger.json or swagger.yaml).
-func URL(url string) func(*Config) {
-	return func(c *Config) {
-		c.URL = url
+			continue
+		}
+
+		if v, ok := fields[k]; ok {
+			edited = append(edited, yaml.MapItem{Key: k, Value: v})
+			delete(fields, k)
+		}
 	}
-}
 
-// DeepLinking true, false.
-func DeepLinking(deepLinking bool) func(*Config) {
-	return func(c *Config) {
-		c.DeepLinking = deepLinking
+	for _, k := range keys {
+		if v, ok := fields[k]; ok {
+			edited = append(edited, yaml.MapItem{Key: k, Value: v})
+		}
 	}
+
+	return yaml.Marshal(edited)
 }
 
-// DocExpansion list, full, none.
-func DocExpansion(docExpansion string) func(*Config) {
-	return func(c *Config) {
-		c.DocExpansion = docExpansion
+// marshalJSON encodes v to JSON without escaping HTML characters.
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
 	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
 }
 
-// DomID #swagger-ui.
-func DomID(domID string) func(*Config) {
-	return func(c *Config) {
-		c.DomID = domID
+// overrideServers replaces the servers of an OpenAPI 3 definition, or the schemes, host and
+// basePath of a Swagger 2.0 one with the first server, either JSON or YAML.
+func overrideServers(doc []byte, contentType string, servers []string) ([]byte, error) {
+	var err error
+
+	keys := []string{"swagger", "schemes", "host", "basePath", "servers"}
+
+	doc, editErr := editSpec(doc, contentType, keys, func(fields map[string]interface{}) {
+		if fields["swagger"] != nil {
+			var first *url.URL
+			if first, err = url.Parse(servers[0]); err != nil {
+				return
+			}
+
+			fields["schemes"] = []interface{}{first.Scheme}
+			fields["host"] = first.Host
+			fields["basePath"] = "/" + strings.TrimPrefix(first.Path, "/")
+
+			return
+		}
+
+		urls := make([]interface{}, 0, len(servers))
+		for _, u := range servers {
+			urls = append(urls, map[string]interface{}{"url": u})
+		}
+
+		fields["servers"] = urls
+	})
+	if editErr != nil {
+		return nil, editErr
 	}
+
+	return doc, err
 }
 
-// InstanceName set the instance name that was used to generate the swagger documents
-// Defaults to swag.Name ("swagger").
-func InstanceName(name string) func(*Config) {
-	return func(c *Config) {
-		c.InstanceName = name
+// setServerVariables sets the defaults of the variables of the servers of an OpenAPI 3 definition,
+// either JSON or YAML, adding them to the enum of the variable and declaring the undeclared ones
+// used by the server url.
+func setServerVariables(doc []byte, contentType string, vars map[string]string) ([]byte, error) {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
 	}
+
+	sort.Strings(names)
+
+	return editSpec(doc, contentType, []string{"servers"}, func(fields map[string]interface{}) {
+		servers, _ := fields["servers"].([]interface{})
+		for _, s := range servers {
+			server := object(s)
+			if server == nil {
+				continue
+			}
+
+			variables := object(server["variables"])
+			for _, name := range names {
+				variable := object(variables[name])
+				if variable == nil {
+					if !strings.Contains(str(server["url"]), "{"+name+"}") {
+						continue
+					}
+
+					variable = map[string]interface{}{}
+				}
+
+				variable["default"] = vars[name]
+				if enum, ok := variable["enum"].([]interface{}); ok && !contains(strs(enum), vars[name]) {
+					variable["enum"] = append(enum, vars[name])
+				}
+
+				if variables == nil {
+					variables = map[string]interface{}{}
+				}
+				variables[name] = variable
+			}
+
+			if variables != nil {
+				server["variables"] = variables
+			}
+		}
+	})
 }
 
-// PersistAuthorization Persist authorization information over browser close/refresh.
-// Defaults to false.
-func PersistAuthorization(persistAuthorization bool) func(*Config) {
-	return func(c *Config) {
-		c.PersistAuthorization = persistAuthorization
-	}
+// hideTags removes the operations with one of the tags from an API definition, either JSON or YAML,
+// with the paths left without operations, the tags and the components only they referenced.
+func hideTags(doc []byte, contentType string, tags []string) ([]byte, error) {
+	keys := []string{"paths", "webhooks", "tags", "definitions", "parameters", "responses", "components"}
+
+	return editSpec(doc, contentType, keys, func(fields map[string]interface{}) {
+		refs := specRefs(fields)
+
+		// OpenAPI 3.1 webhooks are path items too
+		for _, k := range []string{"paths", "webhooks"} {
+			paths := object(fields[k])
+			for p, v := range paths {
+				item, hidden := object(v), false
+				for _, method := range staticMethods {
+					if op := object(item[method]); op != nil && containsAny(strs(op["tags"]), tags) {
+						delete(item, method)
+						hidden = true
+					}
+				}
+
+				if hidden && !hasOperations(item) {
+					delete(paths, p)
+				}
+			}
+		}
+
+		if defs, ok := fields["tags"].([]interface{}); ok {
+			kept := make([]interface{}, 0, len(defs))
+			for _, def := range defs {
+				if !contains(tags, str(object(def)["name"])) {
+					kept = append(kept, def)
+				}
+			}
+
+			fields["tags"] = kept
+		}
+
+		// the components that were not referenced in the first place are kept
+		used := specRefs(fields)
+		for ref := range refs {
+			if !used[ref] {
+				deleteComponent(fields, ref)
+			}
+		}
+	})
 }
 
-// Plugins specifies additional plugins to load into Swagger UI.
-func Plugins(plugins []string) func(*Config) {
-	return func(c *Config) {
-		vs := make([]template.JS, len(plugins))
-		for i, v := range plugins {
-			vs[i] = template.JS(v)
+// truncateExamples replaces the examples of an API definition, either JSON or YAML, whose JSON
+// encoding is larger than limit bytes with a note.
+func truncateExamples(doc []byte, contentType string, limit int) ([]byte, error) {
+	keys := []string{"paths", "webhooks", "definitions", "parameters", "responses", "components"}
+
+	truncate := func(v interface{}) interface{} {
+		b, err := marshalJSON(v)
+		if err != nil || len(b) <= limit {
+			return v
+		}
+
+		return fmt.Sprintf("example truncated: %d bytes exceed the limit of %d bytes", len(b), limit)
+	}
+
+	var walk func(v interface{}, properties bool)
+	walk = func(v interface{}, properties bool) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, e := range v {
+				// the keys of the properties of a schema are property names
+				if !properties {
+					switch k {
+					case "example", "x-example":
+						v[k] = truncate(e)
+
+						continue
+					case "examples":
+						truncateEach(e, truncate)
+
+						continue
+					}
+				}
+
+				walk(v[k], !properties && k == "properties")
+			}
+		case []interface{}:
+			for _, e := range v {
+				walk(e, false)
+			}
 		}
-		c.Plugins = vs
 	}
+
+	return editSpec(doc, contentType, keys, func(fields map[string]interface{}) {
+		for _, k := range keys {
+			walk(fields[k], false)
+		}
+	})
 }
 
-// UIConfig specifies additional SwaggerUIBundle config object properties.
-func UIConfig(props map[string]string) func(*Config) {
-	return func(c *Config) {
-		vs := make(map[template.JS]template.JS, len(props))
-		for k, v := range props {
-			vs[template.JS(k)] = template.JS(v)
+// truncateEach truncates the examples of an examples field: the values of the OpenAPI 3 example
+// objects, the Swagger 2.0 examples by mime type or the items of an OpenAPI 3.1 schema examples.
+func truncateEach(examples interface{}, truncate func(v interface{}) interface{}) {
+	switch examples := examples.(type) {
+	case map[string]interface{}:
+		for k, e := range examples {
+			if example := object(e); example != nil && example["value"] != nil {
+				example["value"] = truncate(example["value"])
+			} else {
+				examples[k] = truncate(e)
+			}
+		}
+	case []interface{}:
+		for i, e := range examples {
+			examples[i] = truncate(e)
 		}
-		c.UIConfig = vs
 	}
 }
 
-// BeforeScript holds JavaScript to be run right before the Swagger UI object is created.
-func BeforeScript(js string) func(*Config) {
-	return func(c *Config) {
-		c.BeforeScript = template.JS(js)
+// specRefs returns the local references of the paths and webhooks of an API definition, including
+// the ones of the components they reference.
+func specRefs(fields map[string]interface{}) map[string]bool {
+	refs := map[string]bool{}
+
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			if ref := str(v["$ref"]); strings.HasPrefix(ref, "#/") && !refs[ref] {
+				refs[ref] = true
+				walk(resolveRef(fields, ref))
+			}
+
+			for _, e := range v {
+				walk(e)
+			}
+		case []interface{}:
+			for _, e := range v {
+				walk(e)
+			}
+		}
 	}
+
+	walk(fields["paths"])
+	walk(fields["webhooks"])
+
+	return refs
 }
 
-// AfterScript holds JavaScript to be run right after the Swagger UI object is created
-// and set on the window.
-func AfterScript(js string) func(*Config) {
-	return func(c *Config) {
-		c.AfterScript = template.JS(js)
+// refUnescaper unescapes the JSON pointer tokens.
+var refUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// refPath returns the unescaped JSON pointer tokens of a local reference.
+func refPath(ref string) []string {
+	tokens := strings.Split(strings.TrimPrefix(ref, "#/"), "/")
+	for i, t := range tokens {
+		tokens[i] = refUnescaper.Replace(t)
 	}
+
+	return tokens
 }
 
-func newConfig(configFns ...func(*Config)) *Config {
-	config := Config{
-		URL:                  "doc.json",
-		DocExpansion:         "list",
-		DomID:                "swagger-ui",
-		InstanceName:         "swagger",
-		DeepLinking:          true,
-		PersistAuthorization: false,
+// resolveRef returns the value of an API definition a local reference points to, if any.
+func resolveRef(fields map[string]interface{}, ref string) interface{} {
+	var v interface{} = fields
+	for _, t := range refPath(ref) {
+		v = object(v)[t]
 	}
 
-	for _, fn := range configFns {
-		fn(&config)
+	return v
+}
+
+// deleteComponent removes the Swagger 2.0 definition, parameter or response or the OpenAPI 3
+// component a local reference points to, then its section if it is left empty.
+func deleteComponent(fields map[string]interface{}, ref string) {
+	tokens := refPath(ref)
+
+	switch {
+	case len(tokens) == 2 && contains([]string{"definitions", "parameters", "responses"}, tokens[0]):
+		delete(object(fields[tokens[0]]), tokens[1])
+	case len(tokens) == 3 && tokens[0] == "components":
+		components := object(fields["components"])
+		section := object(components[tokens[1]])
+		delete(section, tokens[2])
+
+		if len(section) == 0 {
+			delete(components, tokens[1])
+		}
 	}
+}
 
-	if config.InstanceName == "" {
-		config.InstanceName = swag.Name
+// hasOperations reports whether a path item has at least one operation.
+func hasOperations(item map[string]interface{}) bool {
+	for _, method := range staticMethods {
+		if item[method] != nil {
+			return true
+		}
 	}
 
-	return &config
+	return false
 }
 
-// Handler wraps `http.Handler` into `http.HandlerFunc`.
-func Handler(configFns ...func(*Config)) http.HandlerFunc {
-	var once sync.Once
+// containsAny reports whether one of the values is in the list.
+func containsAny(list, values []string) bool {
+	for _, v := range values {
+		if contains(list, v) {
+			return true
+		}
+	}
 
-	config := newConfig(configFns...)
+	return false
+}
 
-	// create a template with name
-	index, _ := template.New("swagger_index.html").Parse(indexTempl)
+// specContentType returns the content type of an API definition file based on its extension.
+func specContentType(name string) string {
+	switch ext := filepath.Ext(name); ext {
+	case ".json":
+		return "application/json; charset=utf-8"
+	case ".yaml", ".yml":
+		return "application/x-yaml; charset=utf-8"
+	default:
+		if contentType := mime.TypeByExtension(ext); contentType != "" {
+			return contentType
+		}
 
-	re := regexp.MustCompile(`^(.*/)([^?].*)?[?|.]*$`)
+		return "application/octet-stream"
+	}
+}
 
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// jsonToYAML converts a JSON document to YAML, preserving the order of object keys.
+func jsonToYAML(doc []byte) ([]byte, error) {
+	var obj yaml.MapSlice
+	if err := yaml.Unmarshal(doc, &obj); err != nil {
+		return nil, err
+	}
 
-			return
-		}
+	return yaml.Marshal(obj)
+}
 
-		matches := re.FindStringSubmatch(r.RequestURI)
+const instancesIndexTempl = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <title>Swagger UI</title>
+</head>
 
-		path := matches[2]
+<body>
+<ul>
+  {{- range .}}
+  <li><a href="./{{.}}/index.html">{{.}}</a></li>
+  {{- end}}
+</ul>
+</body>
 
-		handler := swaggerFiles.Handler
-		once.Do(func() {
-			handler.Prefix = matches[1]
-		})
+</html>
+`
 
-		switch filepath.Ext(path) {
-		case ".html":
-			w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		case ".css":
-			w.Header().Set("Content-Type", "text/css; charset=utf-8")
-		case ".js":
-			w.Header().Set("Content-Type", "application/javascript")
-		case ".png":
-			w.Header().Set("Content-Type", "image/png")
-		case ".json":
-			w.Header().Set("Content-Type", "application/json; charset=utf-8")
-		}
-
-		switch path {
-		case "index.html":
-			_ = index.Execute(w, config)
-		case "doc.json":
-			doc, err := swag.ReadDoc(config.InstanceName)
-			if err != nil {
-				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+const redocTempl = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="UTF-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{if .Title}}{{.Title}}{{else}}API Reference{{end}}</title>
+  {{- if .FaviconURL}}
+  <link rel="icon" href="{{.FaviconURL}}" />
+  {{- else}}
+  <link rel="icon" type="image/png" href="{{.AssetsPrefix}}favicon-32x32.png" sizes="32x32" />
+  <link rel="icon" type="image/png" href="{{.AssetsPrefix}}favicon-16x16.png" sizes="16x16" />
+  {{- end}}
+  {{- range .Stylesheets}}
+  <link rel="stylesheet" href="{{.}}">
+  {{- end}}
+  <style>
+    body {
+      margin: 0;
+      padding: 0;
+    }
+  </style>
+  {{- if .CustomCSS}}
+  <style>
+    {{.CustomCSS}}
+  </style>
+  {{- end}}
+  {{- if .NoIndex}}
+  <meta name="robots" content="noindex, nofollow">
+  {{- end}}
+  {{- if .HeadContent}}
+  {{.HeadContent}}
+  {{- end}}
+</head>
 
-				return
-			}
+<body>
+<div id="{{.DomID}}"></div>
 
-			_, _ = w.Write([]byte(doc))
-		case "":
-			http.Redirect(w, r, handler.Prefix+"index.html", http.StatusMovedPermanently)
-		default:
-			handler.ServeHTTP(w, r)
-		}
-	}
-}
+<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}} src="{{.RedocBundleURL}}"{{with index .Integrity "redoc.standalone.js"}} integrity="{{.}}" crossorigin="anonymous"{{end}}> </script>
+{{- range .Scripts}}
+<script{{if $.Nonce}} nonce="{{$.Nonce}}"{{end}} src="{{.}}"> </script>
+{{- end}}
+<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>
+  Redoc.init({{if .Spec}}{{.Spec}}{{else}}{{.DocURL}}{{end}}, {}, document.getElementById({{.DomID}}))
+</script>
+</body>
+</html>
+`
 
 const indexTempl = `<!-- HTML for static distribution bundle build -->
 <!DOCTYPE html>
-<html lang="en">
+<html lang="{{if .Locale}}{{.Locale}}{{else}}en{{end}}">
 <head>
   <meta charset="UTF-8">
-  <title>Swagger UI</title>
-  <link rel="stylesheet" type="text/css" href="./swagger-ui.css" >
-  <link rel="icon" type="image/png" href="./favicon-32x32.png" sizes="32x32" />
-  <link rel="icon" type="image/png" href="./favicon-16x16.png" sizes="16x16" />
+  <title>{{if .Title}}{{.Title}}{{else}}Swagger UI{{end}}</title>
+  <link rel="stylesheet" type="text/css" href="{{.AssetsPrefix}}swagger-ui.css"{{with index .Integrity "swagger-ui.css"}} integrity="{{.}}"{{if $.CDNBaseURL}} crossorigin="anonymous"{{end}}{{end}} >
+  {{- range .Stylesheets}}
+  <link rel="stylesheet" href="{{.}}">
+  {{- end}}
+  {{- if .FaviconURL}}
+  <link rel="icon" href="{{.FaviconURL}}" />
+  {{- else}}
+  <link rel="icon" type="image/png" href="{{.AssetsPrefix}}favicon-32x32.png" sizes="32x32" />
+  <link rel="icon" type="image/png" href="{{.AssetsPrefix}}favicon-16x16.png" sizes="16x16" />
+  {{- end}}
   <style>
     html
     {
@@ -219,6 +3320,37 @@ const indexTempl = `<!-- HTML for static distribution bundle build -->
       background: #fafafa;
     }
   </style>
+  {{- if .ThemeCSS}}
+  <style{{if eq .Theme "auto"}} media="(prefers-color-scheme: dark)"{{end}}>
+    {{.ThemeCSS}}
+  </style>
+  {{- end}}
+  {{- if not .ShowTopBar}}
+  <style>
+    .topbar { display: none; }
+  </style>
+  {{- end}}
+  {{- if not .ShowModels}}
+  <style>
+    .swagger-ui .models { display: none; }
+  </style>
+  {{- end}}
+  {{- if not .ShowDownloadButton}}
+  <style>
+    .swagger-ui .download-url-wrapper { display: none; }
+  </style>
+  {{- end}}
+  {{- if .CustomCSS}}
+  <style>
+    {{.CustomCSS}}
+  </style>
+  {{- end}}
+  {{- if .NoIndex}}
+  <meta name="robots" content="noindex, nofollow">
+  {{- end}}
+  {{- if .HeadContent}}
+  {{.HeadContent}}
+  {{- end}}
 </head>
 
 <body>
@@ -255,29 +3387,251 @@ const indexTempl = `<!-- HTML for static distribution bundle build -->
   </defs>
 </svg>
 
-<div id="swagger-ui"></div>
+<div id="{{.DomID}}"></div>
 
-<script src="./swagger-ui-bundle.js"> </script>
-<script src="./swagger-ui-standalone-preset.js"> </script>
-<script>
+<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}} src="{{.AssetsPrefix}}swagger-ui-bundle.js"{{with index .Integrity "swagger-ui-bundle.js"}} integrity="{{.}}"{{if $.CDNBaseURL}} crossorigin="anonymous"{{end}}{{end}}> </script>
+<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}} src="{{.AssetsPrefix}}swagger-ui-standalone-preset.js"{{with index .Integrity "swagger-ui-standalone-preset.js"}} integrity="{{.}}"{{if $.CDNBaseURL}} crossorigin="anonymous"{{end}}{{end}}> </script>
+{{- range .Scripts}}
+<script{{if $.Nonce}} nonce="{{$.Nonce}}"{{end}} src="{{.}}"> </script>
+{{- end}}
+<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>
 window.onload = function() {
   {{- if .BeforeScript}}
   {{.BeforeScript}}
   {{- end}}
+  {{- with .AuthorizationPersistKey}}
+  // Persist the authorization under a key of its own
+  ["getItem", "setItem", "removeItem"].forEach((method) => {
+    const fn = Storage.prototype[method]
+    Storage.prototype[method] = function(key, ...args) {
+      if (this === window.localStorage && key === "authorized") {
+        key = {{.}}
+      }
+      return fn.call(this, key, ...args)
+    }
+  })
+  {{- end}}
   // Build a system
   const ui = SwaggerUIBundle({
-    url: "{{.URL}}",
+    {{- if .MinifyConfig}}{{template "compact_config" .}}{{else}}
+    {{- if .Spec}}
+    spec: {{.Spec}},
+    {{- else}}
+    url: "{{.DocURL}}",
+    {{- end}}
+    {{- if .URLs}}
+    urls: {{.URLs}},
+    {{- end}}
     deepLinking: {{.DeepLinking}},
     docExpansion: "{{.DocExpansion}}",
     dom_id: "#{{.DomID}}",
     persistAuthorization: {{.PersistAuthorization}},
-    validatorUrl: null,
+    {{- if .DefaultModelRendering}}
+    defaultModelRendering: "{{.DefaultModelRendering}}",
+    {{- end}}
+    {{- if .FilterExpression}}
+    filter: "{{.FilterExpression}}",
+    {{- else if .Filter}}
+    filter: true,
+    {{- end}}
+    {{- with .DefaultModelsExpandDepth}}
+    defaultModelsExpandDepth: {{.}},
+    {{- end}}
+    {{- with .DefaultModelExpandDepth}}
+    defaultModelExpandDepth: {{.}},
+    {{- end}}
+    {{- if gt .MaxDisplayedTags 0}}
+    maxDisplayedTags: {{.MaxDisplayedTags}},
+    {{- end}}
+    {{- if .OperationsSorter}}
+    operationsSorter: {{.OperationsSorter}},
+    {{- end}}
+    {{- if .TagsSorter}}
+    tagsSorter: {{.TagsSorter}},
+    {{- end}}
+    {{- with .SyntaxHighlight}}
+    {{- if .Activate}}
+    syntaxHighlight: {{.}},
+    {{- else}}
+    syntaxHighlight: false,
+    {{- end}}
+    {{- end}}
+    {{- if gt .RequestTimeoutMs 0}}
+    requestInterceptor: async (req) => {
+      {{- if .RequestInterceptor}}
+      req = await ({{.RequestInterceptor}})(req)
+      {{- end}}
+      const controller = new AbortController()
+      setTimeout(() => controller.abort(new Error("The request timed out after {{.RequestTimeoutMs}} ms")), {{.RequestTimeoutMs}})
+      req.signal = controller.signal
+      return req
+    },
+    {{- else if .RequestInterceptor}}
+    requestInterceptor: {{.RequestInterceptor}},
+    {{- end}}
+    {{- if .ResponseInterceptor}}
+    responseInterceptor: {{.ResponseInterceptor}},
+    {{- end}}
+    {{- if .TryItOutEnabled}}
+    tryItOutEnabled: true,
+    {{- end}}
+    showExtensions: {{if .ShowExtensions}}true{{else}}false{{end}},
+    showCommonExtensions: {{if .ShowCommonExtensions}}true{{else}}false{{end}},
+    {{- if .DisplayRequestDuration}}
+    displayRequestDuration: true,
+    {{- end}}
+    {{- if .WithCredentials}}
+    withCredentials: true,
+    {{- end}}
+    {{- if .RequestSnippetsEnabled}}
+    requestSnippetsEnabled: true,
+    {{- end}}
+    {{- with .RequestSnippets}}
+    requestSnippets: {{.}},
+    {{- end}}
+    {{- if not .ShowMutatedRequest}}
+    showMutatedRequest: false,
+    {{- end}}
+    {{- if .QueryConfigEnabled}}
+    queryConfigEnabled: true,
+    {{- end}}
+    {{- if .ModelPropertyMacro}}
+    modelPropertyMacro: {{.ModelPropertyMacro}},
+    {{- end}}
+    {{- if .ParameterMacro}}
+    parameterMacro: {{.ParameterMacro}},
+    {{- end}}
+    {{- if .SubmitMethods}}
+    supportedSubmitMethods: {{.SubmitMethods}},
+    {{- end}}
+    {{- if .Validator}}
+    validatorUrl: {{.Validator}},
+    {{- end}}
+    {{- if .RedirectURL}}
+    oauth2RedirectUrl: "{{.RedirectURL}}",
+    {{- else}}
+    oauth2RedirectUrl: window.location.origin + window.location.pathname.replace(/[^/]*$/, "oauth2-redirect.html"),
+    {{- end}}
+    {{- end}}
     presets: [
+      {{- range $i, $preset := .Presets}}
+      {{- if $i}},{{end}}
+      {{$preset}}
+      {{- else}}
       SwaggerUIBundle.presets.apis,
       SwaggerUIStandalonePreset
+      {{- end}}
     ],
     plugins: [
       SwaggerUIBundle.plugins.DownloadUrl
+      {{- if .ReadOnly}},
+      () => ({
+        wrapComponents: {
+          authorizeBtn: () => () => null,
+          authorizeOperationBtn: () => () => null,
+          TryItOutButton: () => () => null,
+          execute: () => () => null
+        }
+      })
+      {{- end}}
+      {{- with .FallbackURLs}},
+      (system) => {
+        const fallbacks = {{.}}
+        return {
+          statePlugins: {
+            spec: {
+              wrapActions: {
+                updateLoadingStatus: (ori) => (status) => {
+                  if (status === "failed" && fallbacks.length) {
+                    const url = fallbacks.shift()
+                    setTimeout(() => {
+                      system.specActions.updateUrl(url)
+                      system.specActions.download(url)
+                    })
+                  }
+                  return ori(status)
+                }
+              }
+            }
+          }
+        }
+      }
+      {{- end}}
+      {{- with .SubmitMethodRules}},
+      () => {
+        const rules = {{.}}
+        const match = (pattern, value) => {
+          if (!pattern) {
+            return true
+          }
+          const parts = pattern.split("*")
+          const last = parts.pop()
+          if (!parts.length) {
+            return pattern === value
+          }
+          if (!value.startsWith(parts[0]) || !value.endsWith(last)) {
+            return false
+          }
+          let i = parts[0].length
+          for (const part of parts.slice(1)) {
+            const j = value.indexOf(part, i)
+            if (j === -1) {
+              return false
+            }
+            i = j + part.length
+          }
+          return i <= value.length - last.length
+        }
+        return {
+          wrapComponents: {
+            operation: (Original, { React }) => (props) => {
+              const operation = props.operation
+              const tags = operation.getIn(["op", "tags"])
+              const rule = rules.find((r) => match(r.path, operation.get("path")) &&
+                match((r.method || "").toLowerCase(), operation.get("method")) &&
+                (!r.tag || (tags ? tags.toJS() : []).some((tag) => match(r.tag, tag))))
+              if (!rule) {
+                return React.createElement(Original, props)
+              }
+              return React.createElement(Original, Object.assign({}, props, {
+                operation: operation.set("allowTryItOut", rule.enabled)
+              }))
+            }
+          }
+        }
+      }
+      {{- end}}
+      {{- if not .ShowDownloadButton}},
+      () => ({
+        wrapComponents: {
+          InfoUrl: () => () => null
+        }
+      })
+      {{- end}}
+      {{- with .Translations}},
+      () => ({
+        afterLoad: () => {
+          const translations = {{.}}
+          const translate = (node) => {
+            if (node.nodeType === Node.TEXT_NODE) {
+              const text = node.nodeValue.trim()
+              if (Object.prototype.hasOwnProperty.call(translations, text)) {
+                node.nodeValue = node.nodeValue.replace(text, translations[text])
+              }
+              return
+            }
+            node.childNodes.forEach(translate)
+          }
+          new MutationObserver((mutations) => mutations.forEach((m) => {
+            if (m.type === "characterData") {
+              translate(m.target)
+            } else {
+              m.addedNodes.forEach(translate)
+            }
+          })).observe(document.getElementById({{$.DomID}}), {childList: true, subtree: true, characterData: true})
+        }
+      })
+      {{- end}}
       {{- range $plugin := .Plugins }},
       {{$plugin}}
       {{- end}}
@@ -285,10 +3639,30 @@ window.onload = function() {
     {{- range $k, $v := .UIConfig}}
     {{$k}}: {{$v}},
     {{- end}}
-    layout: "StandaloneLayout"
+    {{- if .MinifyConfig}}{{if or .UIConfig (ne .Layout "BaseLayout")}}
+{{end}}{{if ne .Layout "BaseLayout"}}layout:"{{.Layout}}"{{end}}})
+    {{- else}}
+    layout: "{{.Layout}}"
   })
+    {{- end}}
 
   window.ui = ui
+  {{- with .OAuth2Config}}
+  ui.initOAuth({{.}})
+  {{- end}}
+  {{- if .DevReload}}
+  // Reload the page when the API definition changes
+  let specETag = null
+  setInterval(() => {
+    fetch("{{.DocURL}}", {method: "HEAD", cache: "no-store"}).then((res) => {
+      const etag = res.headers.get("ETag")
+      if (specETag !== null && etag !== specETag) {
+        window.location.reload()
+      }
+      specETag = etag
+    }).catch(() => {})
+  }, 2000)
+  {{- end}}
   {{- if .AfterScript}}
   {{.AfterScript}}
   {{- end}}
@@ -298,3 +3672,61 @@ window.onload = function() {
 
 </html>
 `
+
+// compactConfigTempl is the SwaggerUIBundle configuration of indexTempl written with MinifyConfig: the
+// package generated entries are joined on one line without the ones equal to the Swagger UI defaults,
+// followed by the functions, each on its own line as the user supplied ones may contain line comments.
+const compactConfigTempl = `{{define "compact_config"}}
+{{- if .Spec}}spec:{{js .Spec}},{{else}}url:"{{.DocURL}}",{{end}}
+{{- with .URLs}}urls:{{js .}},{{end}}
+{{- if .DeepLinking}}deepLinking:true,{{end}}
+{{- if ne .DocExpansion "list"}}docExpansion:"{{.DocExpansion}}",{{end}}dom_id:"#{{.DomID}}",
+{{- if .PersistAuthorization}}persistAuthorization:true,{{end}}
+{{- with .DefaultModelRendering}}defaultModelRendering:"{{.}}",{{end}}
+{{- if .FilterExpression}}filter:"{{.FilterExpression}}",{{else if .Filter}}filter:true,{{end}}
+{{- with .DefaultModelsExpandDepth}}defaultModelsExpandDepth:{{js .}},{{end}}
+{{- with .DefaultModelExpandDepth}}defaultModelExpandDepth:{{js .}},{{end}}
+{{- if gt .MaxDisplayedTags 0}}maxDisplayedTags:{{js .MaxDisplayedTags}},{{end}}
+{{- with .SyntaxHighlight}}syntaxHighlight:{{if .Activate}}{{js .}}{{else}}false{{end}},{{end}}
+{{- if .TryItOutEnabled}}tryItOutEnabled:true,{{end}}
+{{- if .ShowExtensions}}showExtensions:true,{{end}}
+{{- if .ShowCommonExtensions}}showCommonExtensions:true,{{end}}
+{{- if .DisplayRequestDuration}}displayRequestDuration:true,{{end}}
+{{- if .WithCredentials}}withCredentials:true,{{end}}
+{{- if .RequestSnippetsEnabled}}requestSnippetsEnabled:true,{{end}}
+{{- with .RequestSnippets}}requestSnippets:{{js .}},{{end}}
+{{- if not .ShowMutatedRequest}}showMutatedRequest:false,{{end}}
+{{- if .QueryConfigEnabled}}queryConfigEnabled:true,{{end}}
+{{- with .SubmitMethods}}supportedSubmitMethods:{{.}},{{end}}
+{{- with .Validator}}validatorUrl:{{.}},{{end}}
+{{- if .RedirectURL}}oauth2RedirectUrl:"{{.RedirectURL}}",
+{{- else}}oauth2RedirectUrl:window.location.origin+window.location.pathname.replace(/[^/]*$/,"oauth2-redirect.html"),{{end}}
+{{- if .OperationsSorter}}
+    operationsSorter: {{.OperationsSorter}},
+{{- end}}
+{{- if .TagsSorter}}
+    tagsSorter: {{.TagsSorter}},
+{{- end}}
+{{- if gt .RequestTimeoutMs 0}}
+    requestInterceptor: async (req) => {
+      {{- if .RequestInterceptor}}
+      req = await ({{.RequestInterceptor}})(req)
+      {{- end}}
+      const controller = new AbortController()
+      setTimeout(() => controller.abort(new Error("The request timed out after {{.RequestTimeoutMs}} ms")), {{.RequestTimeoutMs}})
+      req.signal = controller.signal
+      return req
+    },
+{{- else if .RequestInterceptor}}
+    requestInterceptor: {{.RequestInterceptor}},
+{{- end}}
+{{- if .ResponseInterceptor}}
+    responseInterceptor: {{.ResponseInterceptor}},
+{{- end}}
+{{- if .ModelPropertyMacro}}
+    modelPropertyMacro: {{.ModelPropertyMacro}},
+{{- end}}
+{{- if .ParameterMacro}}
+    parameterMacro: {{.ParameterMacro}},
+{{- end}}
+{{- end}}`
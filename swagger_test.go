@@ -2,15 +2,32 @@ package httpSwagger
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
+	"io/fs"
 	"io/ioutil"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"github.com/andybalholm/brotli"
 	"github.com/stretchr/testify/assert"
+	swaggerFiles "github.com/swaggo/files"
 	"github.com/swaggo/swag"
+	"gopkg.in/yaml.v2"
 )
 
 type mockedSwag struct{}
@@ -82,70 +99,2603 @@ func TestWrapHandler(t *testing.T) {
 	assert.Equal(t, http.StatusMethodNotAllowed, performRequest(http.MethodPut, "/swagger/index.html", router).Code)
 }
 
+func TestHandlerWithError(t *testing.T) {
+	_, err := HandlerWithError(InstanceName("unregistered"))
+	assert.Error(t, err)
+
+	swag.Register("registered", &mockedSwag{})
+
+	h, err := HandlerWithError(InstanceName("registered"))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/doc.json", h).Code)
+}
+
+func TestSpecFormatYAML(t *testing.T) {
+	swag.Register("yaml", &mockedSwag{})
+
+	h := Handler(InstanceName("yaml"), SpecFormat("yaml"))
+
+	w := performRequest(http.MethodGet, "/doc.yaml", h)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-yaml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "swagger: \"2.0\"\ninfo:\n"))
+
+	w = performRequest(http.MethodGet, "/doc.json", h)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "doc.yaml", newConfig(SpecFormat("yaml")).URL)
+}
+
+func TestSpecFormats(t *testing.T) {
+	swag.Register("formats", &mockedSwag{})
+
+	h := Handler(InstanceName("formats"))
+
+	w1 := performRequest(http.MethodGet, "/swagger/doc.json", h)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w1.Header().Get("Content-Type"))
+
+	w2 := performRequest(http.MethodGet, "/swagger/doc.yaml", h)
+	assert.Equal(t, http.StatusOK, w2.Code)
+	assert.Equal(t, "application/x-yaml; charset=utf-8", w2.Header().Get("Content-Type"))
+
+	var fromJSON, fromYAML interface{}
+	assert.NoError(t, yaml.Unmarshal(w1.Body.Bytes(), &fromJSON))
+	assert.NoError(t, yaml.Unmarshal(w2.Body.Bytes(), &fromYAML))
+	assert.Equal(t, fromJSON, fromYAML)
+
+	h = Handler(InstanceName("formats"), SpecPath("openapi.yaml"), SpecFormat("yaml"))
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/swagger/openapi.json", h).Code)
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/doc.json", h).Code)
+}
+
+func TestNewHandler(t *testing.T) {
+	var h http.Handler = NewHandler(Title("Petstore"))
+
+	sh, ok := h.(*SwaggerHandler)
+	assert.True(t, ok)
+	assert.Equal(t, "Petstore", sh.Config().Title)
+	assert.Equal(t, "doc.json", sh.Config().URL)
+
+	sh.Config().Title = "Petstore v2"
+	assert.Contains(t, performRequest(http.MethodGet, "/index.html", h).Body.String(), "<title>Petstore v2</title>")
+
+	assert.Panics(t, func() { NewHandler(Layout("CustomLayout"), StrictValidation(true)) })
+}
+
+func TestHandlerFromRequest(t *testing.T) {
+	h := HandlerFromRequest(func(r *http.Request) []func(*Config) {
+		tenant := r.Header.Get("X-Tenant")
+
+		return []func(*Config){
+			Title(tenant),
+			URL("/" + tenant + "/doc.json"),
+			Layout(r.Header.Get("X-Layout")),
+			StrictValidation(true),
+		}
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/index.html", nil)
+	r.Header.Set("X-Tenant", "acme")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<title>acme</title>")
+	assert.Contains(t, w.Body.String(), `url: "\/acme\/doc.json",`)
+
+	r.Header.Set("X-Layout", "CustomLayout")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestHandlerFromRequestCache(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+
+	h := HandlerFromRequest(func(r *http.Request) []func(*Config) {
+		return []func(*Config){
+			Title(r.Header.Get("X-Tenant")),
+			DocExpansion("bogus"),
+			Logger(logger),
+		}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			tenant := []string{"acme", "globex"}[i%2]
+			for j := 0; j < 20; j++ {
+				r := httptest.NewRequest(http.MethodGet, "/"+tenant+"/index.html", nil)
+				r.Header.Set("X-Tenant", tenant)
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+				assert.Contains(t, w.Body.String(), "<title>"+tenant+"</title>")
+
+				r = httptest.NewRequest(http.MethodGet, "/"+tenant+"/", nil)
+				r.Header.Set("X-Tenant", tenant)
+				w = httptest.NewRecorder()
+				h.ServeHTTP(w, r)
+				assert.Equal(t, "/"+tenant+"/index.html", w.Header().Get("Location"))
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	// the configuration of each tenant is only validated once, unless both were built concurrently
+	assert.LessOrEqual(t, strings.Count(buf.String(), `httpSwagger: invalid doc expansion "bogus"`), 8)
+	assert.GreaterOrEqual(t, strings.Count(buf.String(), `httpSwagger: invalid doc expansion "bogus"`), 2)
+
+	key, ok := configKey(newConfig(Title("acme")))
+	assert.True(t, ok)
+
+	other, _ := configKey(newConfig(Title("globex")))
+	assert.NotEqual(t, key, other)
+
+	same, _ := configKey(newConfig(Title("acme")))
+	assert.Equal(t, key, same)
+
+	_, ok = configKey(newConfig(SpecProvider(func(context.Context) ([]byte, string, error) { return nil, "", nil })))
+	assert.False(t, ok)
+}
+
+func TestIndexCache(t *testing.T) {
+	h := NewHandler(Title("Petstore"))
+
+	w1 := performRequest(http.MethodGet, "/index.html", h)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, strconv.Itoa(w1.Body.Len()), w1.Header().Get("Content-Length"))
+
+	h.Config().Title = "Changed"
+	w2 := performRequest(http.MethodGet, "/index.html", h)
+	assert.Equal(t, w1.Body.String(), w2.Body.String())
+
+	nonce := 0
+	h = NewHandler(CSPNonceFunc(func(*http.Request) string {
+		nonce++
+
+		return strconv.Itoa(nonce)
+	}))
+	assert.Contains(t, performRequest(http.MethodGet, "/index.html", h).Body.String(), `nonce="1"`)
+	assert.Contains(t, performRequest(http.MethodGet, "/index.html", h).Body.String(), `nonce="2"`)
+}
+
+func TestMultiHandler(t *testing.T) {
+	swag.Register("multi_v1", &mockedSwag{})
+	swag.Register("multi_v2", &mockedSwag{})
+
+	router := http.NewServeMux()
+	router.Handle("/docs/", MultiHandler(map[string]func(*Config){
+		"multi_v1": nil,
+		"multi_v2": Title("API v2"),
+	}))
+
+	w1 := performRequest(http.MethodGet, "/docs/", router)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Contains(t, w1.Body.String(), `<li><a href="./multi_v1/index.html">multi_v1</a></li>
+  <li><a href="./multi_v2/index.html">multi_v2</a></li>`)
+
+	w2 := performRequest(http.MethodGet, "/docs/multi_v2/index.html", router)
+	assert.Equal(t, http.StatusOK, w2.Code)
+	assert.Contains(t, w2.Body.String(), "<title>API v2</title>")
+
+	w3 := performRequest(http.MethodGet, "/docs/multi_v1/doc.json", router)
+	assert.Equal(t, http.StatusOK, w3.Code)
+	assert.Equal(t, (&mockedSwag{}).ReadDoc(), w3.Body.String())
+
+	w4 := performRequest(http.MethodGet, "/docs/multi_v1", router)
+	assert.Equal(t, http.StatusMovedPermanently, w4.Code)
+	assert.Equal(t, "/docs/multi_v1/", w4.Header().Get("Location"))
+
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/docs/multi_v2/swagger-ui.css", router).Code)
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/docs/unknown", router).Code)
+
+	// each instance redirects to its own index page, whichever was requested before
+	for _, name := range []string{"multi_v1", "multi_v2", "multi_v1", "multi_v2"} {
+		w := performRequest(http.MethodGet, "/docs/"+name+"/", router)
+		assert.Equal(t, http.StatusMovedPermanently, w.Code)
+		assert.Equal(t, "/docs/"+name+"/index.html", w.Header().Get("Location"))
+	}
+
+	router = http.NewServeMux()
+	router.Handle("/a/", Handler(InstanceName("multi_v1")))
+	router.Handle("/b/", Handler(InstanceName("multi_v2")))
+
+	for _, prefix := range []string{"/a/", "/b/", "/a/"} {
+		assert.Equal(t, prefix+"index.html", performRequest(http.MethodGet, prefix, router).Header().Get("Location"))
+	}
+}
+
+type stringSwag string
+
+func (s stringSwag) ReadDoc() string {
+	return string(s)
+}
+
+func TestInstanceURLs(t *testing.T) {
+	cfg := Config{}
+	configFunc := InstanceURLs("v1", "v2")
+	configFunc(&cfg)
+	assert.Equal(t, []string{"v1", "v2"}, cfg.Instances)
+
+	newCfg := newConfig(URLs([]URLsConfig{{URL: "doc.yaml", Name: "v0"}}), InstanceURLs("v1", "v2"), SpecFormat("yaml"))
+	assert.Equal(t, []URLsConfig{{URL: "doc.yaml", Name: "v0"}, {URL: "v1/doc.yaml", Name: "v1"}, {URL: "v2/doc.yaml", Name: "v2"}}, newTemplateData(newCfg, "").URLs)
+	assert.Equal(t, []URLsConfig{{URL: "doc.yaml", Name: "v0"}}, newCfg.URLs)
+	assert.Equal(t, "StandaloneLayout", newCfg.Layout)
+	assert.Equal(t, "StandaloneLayout", newConfig(InstanceURLs("v1")).Layout)
+
+	// the configuration is resolved once
+	page, err := RenderHTML(InstanceURLs("v1", "v2"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(page), `"name":"v1"`))
+	assert.Contains(t, string(page), `urls: [{"url":"v1/doc.json","name":"v1"},{"url":"v2/doc.json","name":"v2"}],`)
+
+	swag.Register("instance_v1", stringSwag(`{"info":{"version":"1"}}`))
+	swag.Register("instance_v2", stringSwag(`{"info":{"version":"2"}}`))
+
+	_, err = HandlerWithError(InstanceURLs("instance_v1", "unregistered"))
+	assert.Error(t, err)
+
+	h, err := HandlerWithError(InstanceName("instance_v1"), InstanceURLs("instance_v1", "instance_v2"))
+	assert.NoError(t, err)
+
+	router := http.NewServeMux()
+	router.Handle("/swagger/", h)
+
+	w := performRequest(http.MethodGet, "/swagger/index.html", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `urls: [{"url":"instance_v1/doc.json","name":"instance_v1"},{"url":"instance_v2/doc.json","name":"instance_v2"}],`)
+
+	w = performRequest(http.MethodGet, "/swagger/instance_v2/doc.json", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"info":{"version":"2"}}`, w.Body.String())
+
+	w = performRequest(http.MethodGet, "/swagger/doc.json", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"info":{"version":"1"}}`, w.Body.String())
+}
+
+func TestSpecProvider(t *testing.T) {
+	spec := []byte(`openapi: 3.0.0`)
+	provider := func(context.Context) ([]byte, string, error) {
+		return spec, "application/yaml", nil
+	}
+
+	h, err := HandlerWithError(InstanceName("unregistered"), SpecProvider(provider))
+	assert.NoError(t, err)
+
+	w := performRequest(http.MethodGet, "/doc.json", h)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.Equal(t, string(spec), w.Body.String())
+
+	h = Handler(SpecProvider(func(context.Context) ([]byte, string, error) {
+		return nil, "", errors.New("unavailable")
+	}))
+	w = performRequest(http.MethodGet, "/doc.json", h)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"error":"API definition unavailable"}`, w.Body.String())
+}
+
+func TestMaxExampleBytes(t *testing.T) {
+	cfg := Config{}
+	configFunc := MaxExampleBytes(16)
+	configFunc(&cfg)
+	assert.Equal(t, 16, cfg.MaxExampleBytes)
+
+	swag.Register("TestMaxExampleBytes", stringSwag(`{
+  "openapi": "3.1.0",
+  "paths": {
+    "/pets": {"post": {
+      "parameters": [{"name": "q", "in": "query", "example": "dog"}],
+      "requestBody": {"content": {"application/json": {"examples": {
+        "small": {"value": {"id": 1}},
+        "large": {"value": {"name": "a very long pet name"}}
+      }}}},
+      "responses": {"200": {"content": {"application/json": {"example": [1, 2, 3, 4, 5, 6, 7, 8, 9]}}}}
+    }}
+  },
+  "components": {"schemas": {"Pet": {
+    "examples": [{"id": 1}, "a very long pet name"],
+    "properties": {"example": {"type": "string", "example": "short"}}
+  }}}
+}`))
+
+	h := Handler(InstanceName("TestMaxExampleBytes"), MaxExampleBytes(16))
+
+	var spec map[string]interface{}
+	assert.NoError(t, json.Unmarshal(performRequest(http.MethodGet, "/doc.json", h).Body.Bytes(), &spec))
+
+	op := object(object(object(spec["paths"])["/pets"])["post"])
+	assert.Equal(t, "dog", object(op["parameters"].([]interface{})[0])["example"])
+
+	examples := object(object(object(object(op["requestBody"])["content"])["application/json"])["examples"])
+	assert.Equal(t, map[string]interface{}{"id": float64(1)}, object(examples["small"])["value"])
+	assert.Equal(t, "example truncated: 31 bytes exceed the limit of 16 bytes", object(examples["large"])["value"])
+
+	response := object(object(object(object(op["responses"])["200"])["content"])["application/json"])
+	assert.Equal(t, "example truncated: 19 bytes exceed the limit of 16 bytes", response["example"])
+
+	pet := object(object(object(spec["components"])["schemas"])["Pet"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": float64(1)}, "example truncated: 22 bytes exceed the limit of 16 bytes"}, pet["examples"])
+	assert.Equal(t, map[string]interface{}{"type": "string", "example": "short"}, object(pet["properties"])["example"])
+
+	h = Handler(InstanceName("TestMaxExampleBytes"))
+	assert.Contains(t, performRequest(http.MethodGet, "/doc.json", h).Body.String(), "a very long pet name")
+}
+
+func TestHiddenTags(t *testing.T) {
+	cfg := Config{}
+	configFunc := HiddenTags("internal", " ", "admin ")
+	configFunc(&cfg)
+	assert.Equal(t, []string{"internal", "admin"}, cfg.HiddenTags)
+
+	swag.Register("TestHiddenTags", stringSwag(`{
+  "swagger": "2.0",
+  "tags": [{"name": "pets"}, {"name": "internal"}],
+  "paths": {
+    "/pets": {"get": {"tags": ["pets"], "responses": {"200": {"schema": {"$ref": "#/definitions/Pet"}}}}},
+    "/pets/{id}": {
+      "get": {"tags": ["pets"], "responses": {"200": {"schema": {"$ref": "#/definitions/Pet"}}}},
+      "delete": {"tags": ["internal"], "responses": {"204": {"$ref": "#/responses/Deleted"}}}
+    },
+    "/audit": {"get": {"tags": ["internal"], "responses": {"200": {"schema": {"$ref": "#/definitions/Audit"}}}}}
+  },
+  "definitions": {
+    "Audit": {"properties": {"pet": {"$ref": "#/definitions/Pet"}, "user": {"$ref": "#/definitions/User"}}},
+    "Category": {"type": "object"},
+    "Error": {"type": "object"},
+    "Pet": {"properties": {"category": {"$ref": "#/definitions/Category"}}},
+    "User": {"type": "object"}
+  },
+  "responses": {"Deleted": {"description": "Deleted"}}
+}`))
+
+	h := Handler(InstanceName("TestHiddenTags"), HiddenTags("internal"))
+
+	var spec map[string]interface{}
+	assert.NoError(t, json.Unmarshal(performRequest(http.MethodGet, "/doc.json", h).Body.Bytes(), &spec))
+
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "pets"}}, spec["tags"])
+	assert.Equal(t, []string{"/pets", "/pets/{id}"}, sortedKeys(object(spec["paths"])))
+	assert.Equal(t, []string{"get"}, sortedKeys(object(object(spec["paths"])["/pets/{id}"])))
+	// Category is only referenced by Pet, Error was not referenced in the first place
+	assert.Equal(t, []string{"Category", "Error", "Pet"}, sortedKeys(object(spec["definitions"])))
+	assert.Empty(t, spec["responses"])
+
+	swag.Register("TestHiddenTagsOpenAPI", stringSwag(`{
+  "openapi": "3.0.3",
+  "paths": {
+    "/pets": {"get": {"tags": ["pets"], "responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}}}},
+    "/admin": {"post": {"tags": ["internal"], "requestBody": {"$ref": "#/components/requestBodies/Admin"}}}
+  },
+  "components": {
+    "requestBodies": {"Admin": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet~1Admin"}}}}},
+    "schemas": {"Pet": {"type": "object"}, "Pet/Admin": {"type": "object"}},
+    "securitySchemes": {"key": {"type": "apiKey"}}
+  }
+}`))
+
+	h = Handler(InstanceName("TestHiddenTagsOpenAPI"), SpecFormat("yaml"), HiddenTags("internal"))
+
+	var yamlSpec map[interface{}]interface{}
+	assert.NoError(t, yaml.Unmarshal(performRequest(http.MethodGet, "/doc.yaml", h).Body.Bytes(), &yamlSpec))
+
+	assert.Equal(t, "3.0.3", yamlSpec["openapi"])
+	components := object(jsonValue(yamlSpec["components"]))
+	assert.Equal(t, []string{"schemas", "securitySchemes"}, sortedKeys(components))
+	assert.Equal(t, []string{"Pet"}, sortedKeys(object(components["schemas"])))
+	assert.Equal(t, []string{"/pets"}, sortedKeys(object(jsonValue(yamlSpec["paths"]))))
+
+	h = Handler(InstanceName("TestHiddenTags"), HiddenTags("internal"), StaticRender(true))
+	body := performRequest(http.MethodGet, "/index.html", h).Body.String()
+	assert.Contains(t, body, "/pets/{id}")
+	assert.NotContains(t, body, "/audit")
+	assert.NotContains(t, body, "Audit")
+}
+
+func TestSpecTransform(t *testing.T) {
+	transform := func(_ context.Context, raw []byte) ([]byte, error) {
+		return bytes.ReplaceAll(raw, []byte(`"/internal":{},`), nil), nil
+	}
+
+	cfg := Config{}
+	configFunc := SpecTransform(transform)
+	configFunc(&cfg)
+	assert.NotNil(t, cfg.SpecTransform)
+
+	swag.Register("TestSpecTransform", stringSwag(`{"info":{"title":"Pets"},"paths":{"/internal":{},"/pets":{}}}`))
+
+	h := Handler(InstanceName("TestSpecTransform"), SpecTransform(transform))
+	assert.Equal(t, `{"info":{"title":"Pets"},"paths":{"/pets":{}}}`, performRequest(http.MethodGet, "/doc.json", h).Body.String())
+
+	h = Handler(InstanceName("TestSpecTransform"), SpecTransform(transform), InlineSpec(true))
+	assert.Contains(t, performRequest(http.MethodGet, "/index.html", h).Body.String(), `spec: {"info":{"title":"Pets"},"paths":{"/pets":{}}},`)
+
+	h = Handler(InstanceName("TestSpecTransform"), SpecTransform(transform), StaticRender(true))
+	assert.NotContains(t, performRequest(http.MethodGet, "/index.html", h).Body.String(), "internal")
+
+	var buf bytes.Buffer
+	h = Handler(InstanceName("TestSpecTransform"), Logger(log.New(&buf, "", 0)), SpecTransform(func(context.Context, []byte) ([]byte, error) {
+		return nil, errors.New("redaction failed")
+	}))
+
+	w := performRequest(http.MethodGet, "/doc.json", h)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"error":"API definition unavailable"}`, w.Body.String())
+	assert.Equal(t, "httpSwagger: transforming API definition: redaction failed\n", buf.String())
+}
+
+func TestSpecUnavailable(t *testing.T) {
+	var buf bytes.Buffer
+
+	w := performRequest(http.MethodGet, "/doc.json", Handler(InstanceName("TestSpecUnavailable"), Logger(log.New(&buf, "", 0))))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"error":"swagger doc not found for instance TestSpecUnavailable"}`, w.Body.String())
+	assert.Contains(t, buf.String(), "httpSwagger: reading API definition: ")
+
+	swag.Register("TestSpecUnavailableEmpty", stringSwag(""))
+
+	buf.Reset()
+	w = performRequest(http.MethodGet, "/doc.json", Handler(InstanceName("TestSpecUnavailableEmpty"), Logger(log.New(&buf, "", 0))))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"error":"swagger doc not found for instance TestSpecUnavailableEmpty"}`, w.Body.String())
+	assert.Equal(t, "httpSwagger: reading API definition: httpSwagger: empty API definition\n", buf.String())
+}
+
+func TestSpecProviderContext(t *testing.T) {
+	var buf bytes.Buffer
+
+	h := Handler(Logger(log.New(&buf, "", 0)), SpecProvider(func(ctx context.Context) ([]byte, string, error) {
+		<-ctx.Done()
+
+		return nil, "", ctx.Err()
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/doc.json", nil).WithContext(ctx))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan struct{})
+	w = httptest.NewRecorder()
+	go func() {
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/doc.json", nil).WithContext(ctx))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handler did not return after the request was canceled")
+	}
+	assert.Empty(t, w.Body.String())
+	assert.Empty(t, buf.String())
+}
+
+func TestSpecFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"api/openapi.yaml": {Data: []byte(`openapi: 3.0.0`)},
+	}
+
+	h, err := HandlerWithError(InstanceName("unregistered"), SpecFS(fsys, "api/openapi.yaml"))
+	assert.NoError(t, err)
+
+	w := performRequest(http.MethodGet, "/doc.json", h)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-yaml; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `openapi: 3.0.0`, w.Body.String())
+
+	h = Handler(SpecFS(fsys, "api/missing.json"))
+	assert.Equal(t, http.StatusInternalServerError, performRequest(http.MethodGet, "/doc.json", h).Code)
+}
+
+func TestLastModified(t *testing.T) {
+	modTime := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"openapi.json": {Data: []byte(`{"openapi":"3.0.0"}`), ModTime: modTime},
+	}
+
+	h := Handler(SpecFS(fsys, "openapi.json"))
+
+	w := performRequest(http.MethodGet, "/doc.json", h)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Wed, 01 Jun 2022 12:00:00 GMT", w.Header().Get("Last-Modified"))
+
+	for since, status := range map[string]int{
+		"Wed, 01 Jun 2022 12:00:00 GMT": http.StatusNotModified,
+		"Thu, 02 Jun 2022 12:00:00 GMT": http.StatusNotModified,
+		"Tue, 31 May 2022 12:00:00 GMT": http.StatusOK,
+		"invalid":                       http.StatusOK,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+		r.Header.Set("If-Modified-Since", since)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, status, w.Code, since)
+	}
+
+	swag.Register("last_modified", &mockedSwag{})
+
+	w = performRequest(http.MethodGet, "/doc.json", Handler(InstanceName("last_modified")))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Last-Modified"))
+
+	w = performRequest(http.MethodGet, "/doc.json", Handler(SpecProvider(func(context.Context) ([]byte, string, error) {
+		return []byte(`{}`), "application/json", nil
+	})))
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+}
+
+func TestAssetOverrides(t *testing.T) {
+	fsys := http.FS(fstest.MapFS{
+		"swagger-ui.css": {Data: []byte(`body{}`)},
+		"css":            {Mode: fs.ModeDir},
+	})
+
+	cfg := Config{}
+	configFunc := AssetOverrides(fsys)
+	configFunc(&cfg)
+	assert.Equal(t, fsys, cfg.AssetOverrides)
+
+	router := http.NewServeMux()
+	router.Handle("/swagger/", Handler(AssetOverrides(fsys)))
+
+	w := performRequest(http.MethodGet, "/swagger/swagger-ui.css", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/css; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `body{}`, w.Body.String())
+
+	w = performRequest(http.MethodGet, "/swagger/swagger-ui-bundle.js", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(swaggerFiles.FileSwaggerUIBundleJs), w.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/css", router).Code)
+
+	body := performRequest(http.MethodGet, "/swagger/index.html", router).Body.String()
+	assert.Contains(t, body, `href="./swagger-ui.css" >`)
+	assert.Contains(t, body, `src="./swagger-ui-bundle.js" integrity="`)
+}
+
+func TestAssetFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"swagger-ui.css": {Data: []byte(`body{}`)},
+	}
+
+	cfg := Config{}
+	configFunc := AssetFS(fsys)
+	configFunc(&cfg)
+	assert.Equal(t, fsys, cfg.AssetFS)
+
+	router := http.NewServeMux()
+	router.Handle("/swagger/", Handler(AssetFS(fsys)))
+
+	w := performRequest(http.MethodGet, "/swagger/swagger-ui.css", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/css; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `body{}`, w.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/swagger-ui-bundle.js", router).Code)
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/swagger/index.html", router).Code)
+}
+
+func TestRegisterRoutes(t *testing.T) {
+	swag.Register("TestRegisterRoutes", &mockedSwag{})
+
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, "/docs/swagger", InstanceName("TestRegisterRoutes"))
+
+	w := performRequest(http.MethodGet, "/docs/swagger?urls.primaryName=v2", mux)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/docs/swagger/?urls.primaryName=v2", w.Header().Get("Location"))
+
+	w = performRequest(http.MethodGet, "/docs/swagger/", mux)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/docs/swagger/index.html", w.Header().Get("Location"))
+
+	for _, target := range []string{"/docs/swagger/index.html", "/docs/swagger/doc.json", "/docs/swagger/swagger-ui-bundle.js"} {
+		assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, target, mux).Code, target)
+	}
+
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/docs/swagger/notfound", mux).Code)
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/docs", mux).Code)
+
+	mux = http.NewServeMux()
+	RegisterRoutes(mux, "/", InstanceName("TestRegisterRoutes"))
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/doc.json", mux).Code)
+}
+
+func TestMountRedirect(t *testing.T) {
+	router := http.NewServeMux()
+	router.Handle("/swagger", http.StripPrefix("/swagger", Handler()))
+	router.Handle("/docs", Handler(BasePath("/docs/")))
+
+	w := performRequest(http.MethodGet, "/swagger", router)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/swagger/", w.Header().Get("Location"))
+
+	w = performRequest(http.MethodGet, "/swagger?urls.primaryName=v2", router)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/swagger/?urls.primaryName=v2", w.Header().Get("Location"))
+
+	w = performRequest(http.MethodGet, "/docs", router)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/docs/", w.Header().Get("Location"))
+}
+
+func TestMountStyles(t *testing.T) {
+	swag.Register("mount", &mockedSwag{})
+
+	router := http.NewServeMux()
+	router.Handle("/raw/", Handler(InstanceName("mount")))
+	router.Handle("/stripped/", http.StripPrefix("/stripped", Handler(InstanceName("mount"))))
+
+	for _, prefix := range []string{"/raw/", "/stripped/"} {
+		assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, prefix+"index.html", router).Code, prefix)
+		assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, prefix+"doc.json", router).Code, prefix)
+		assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, prefix+"swagger-ui.css", router).Code, prefix)
+	}
+
+	r, err := http.NewRequest(http.MethodGet, "/docs/doc.json", nil)
+	assert.NoError(t, err)
+	w := httptest.NewRecorder()
+	Handler(InstanceName("mount")).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, (&mockedSwag{}).ReadDoc(), w.Body.String())
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	h := Handler()
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		w := performRequest(method, "/index.html", h)
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+	}
+
+	w := performRequest(http.MethodPost, "/docs/", MultiHandler(nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"))
+}
+
+func TestHead(t *testing.T) {
+	swag.Register("head", &mockedSwag{})
+
+	h := Handler(InstanceName("head"))
+
+	for target, contentType := range map[string]string{
+		"/index.html":           "text/html; charset=utf-8",
+		"/doc.json":             "application/json; charset=utf-8",
+		"/swagger-ui-bundle.js": "application/javascript",
+	} {
+		get := performRequest(http.MethodGet, target, h)
+
+		w := performRequest(http.MethodHead, target, h)
+		assert.Equal(t, http.StatusOK, w.Code, target)
+		assert.Equal(t, contentType, w.Header().Get("Content-Type"), target)
+		assert.Equal(t, strconv.Itoa(get.Body.Len()), w.Header().Get("Content-Length"), target)
+		assert.Equal(t, 0, w.Body.Len(), target)
+	}
+}
+
 func performRequest(method, target string, h http.Handler) *httptest.ResponseRecorder {
 	r := httptest.NewRequest(method, target, nil)
 	w := httptest.NewRecorder()
 
-	h.ServeHTTP(w, r)
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestURL(t *testing.T) {
+	expected := "https://github.com/swaggo/http-swagger"
+	cfg := Config{}
+	configFunc := URL(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.URL)
+}
+
+func TestDeepLinking(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := DeepLinking(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.DeepLinking)
+
+	router := http.NewServeMux()
+	router.Handle("/swagger/", Handler(DomID("#docs"), URLs([]URLsConfig{{URL: "doc.json", Name: "v1"}})))
+
+	w := performRequest(http.MethodGet, "/swagger/index.html", router)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	body := w.Body.String()
+	assert.Contains(t, body, `<div id="docs"></div>`)
+	assert.Contains(t, body, `deepLinking:  true ,`)
+	assert.Contains(t, body, `dom_id: "#docs",`)
+	assert.Contains(t, body, "SwaggerUIBundle.presets.apis,\n      SwaggerUIStandalonePreset")
+	assert.Contains(t, body, `layout: "StandaloneLayout"`)
+}
+
+func TestDocExpansion(t *testing.T) {
+	expected := "https://github.com/swaggo/docs"
+	cfg := Config{}
+	configFunc := DocExpansion(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.DocExpansion)
+}
+
+func TestDomID(t *testing.T) {
+	expected := "#swagger-ui"
+	cfg := Config{}
+	configFunc := DomID(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.DomID)
+
+	for _, domID := range []string{"api-docs", "#api-docs"} {
+		page, err := RenderHTML(DomID(domID))
+		assert.NoError(t, err)
+		assert.Contains(t, string(page), `<div id="api-docs"></div>`, domID)
+		assert.Contains(t, string(page), `dom_id: "#api-docs",`, domID)
+	}
+}
+
+func TestInstanceName(t *testing.T) {
+	var cfg Config
+
+	assert.Equal(t, "", cfg.InstanceName)
+
+	expected := swag.Name
+	InstanceName(expected)(&cfg)
+	assert.Equal(t, expected, cfg.InstanceName)
+
+	expected = "custom_name"
+	InstanceName(expected)(&cfg)
+	assert.Equal(t, expected, cfg.InstanceName)
+
+	newCfg := newConfig(InstanceName(""))
+	assert.Equal(t, swag.Name, newCfg.InstanceName)
+}
+
+func TestPersistAuthorization(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := PersistAuthorization(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.PersistAuthorization)
+}
+
+func TestRequireHTTPS(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := RequireHTTPS(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.RequireHTTPS)
+
+	w := performRequest(http.MethodGet, "http://example.com/swagger/index.html?x=1", Handler(RequireHTTPS(true)))
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "https://example.com/swagger/index.html?x=1", w.Header().Get("Location"))
+
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "https://example.com/swagger/index.html", Handler(RequireHTTPS(true))).Code)
+
+	r := httptest.NewRequest(http.MethodGet, "http://10.0.0.1/swagger/index.html", nil)
+	r.Header.Set("X-Forwarded-Proto", "http")
+	r.Header.Set("X-Forwarded-Host", "docs.example.com")
+	r.Header.Set("X-Forwarded-Prefix", "/api")
+	w = httptest.NewRecorder()
+	Handler(RequireHTTPS(true), TrustForwardedHeaders(true)).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "https://docs.example.com/api/swagger/index.html", w.Header().Get("Location"))
+
+	r.Header.Set("X-Forwarded-Proto", "https")
+	w = httptest.NewRecorder()
+	Handler(RequireHTTPS(true), TrustForwardedHeaders(true)).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	Handler(RequireHTTPS(true)).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusPermanentRedirect, w.Code)
+	assert.Equal(t, "https://10.0.0.1/swagger/index.html", w.Header().Get("Location"))
+}
+
+func TestNegotiateSpecFormat(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := NegotiateSpecFormat(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.NegotiateSpecFormat)
+
+	swag.Register("negotiate", &mockedSwag{})
+
+	h := Handler(InstanceName("negotiate"), NegotiateSpecFormat(true))
+
+	for accept, contentType := range map[string]string{
+		"":                              "application/json; charset=utf-8",
+		"application/json":              "application/json; charset=utf-8",
+		"application/x-yaml":            "application/x-yaml; charset=utf-8",
+		"text/html, text/yaml;q=0.9":    "application/x-yaml; charset=utf-8",
+		"application/vnd.oai.openapi+*": "application/json; charset=utf-8",
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusOK, w.Code, accept)
+		assert.Equal(t, contentType, w.Header().Get("Content-Type"), accept)
+		assert.Equal(t, "Accept", w.Header().Get("Vary"), accept)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+	r.Header.Set("Accept", "application/x-yaml")
+	w := httptest.NewRecorder()
+	Handler(InstanceName("negotiate")).ServeHTTP(w, r)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+func TestPrettyJSON(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := PrettyJSON(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.PrettyJSON)
+
+	provider := SpecProvider(func(context.Context) ([]byte, string, error) {
+		return []byte(`{"paths":{"/pets":{}}}`), "application/json; charset=utf-8", nil
+	})
+
+	w := performRequest(http.MethodGet, "/doc.json", Handler(provider))
+	assert.Equal(t, `{"paths":{"/pets":{}}}`, w.Body.String())
+
+	h := NewHandler(provider, PrettyJSON(true))
+	for i := 0; i < 2; i++ {
+		w = performRequest(http.MethodGet, "/doc.json", h)
+		assert.Equal(t, "{\n  \"paths\": {\n    \"/pets\": {}\n  }\n}", w.Body.String())
+	}
+	assert.Len(t, h.pretty, 1)
+}
+
+func TestFallbackURLs(t *testing.T) {
+	cfg := Config{}
+	configFunc := FallbackURLs("https://mirror.example.com/doc.json")
+	configFunc(&cfg)
+	assert.Equal(t, []string{"https://mirror.example.com/doc.json"}, cfg.FallbackURLs)
+
+	page, err := RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), "fallbacks")
+
+	page, err = RenderHTML(FallbackURLs("https://mirror.example.com/doc.json", "doc.json"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `const fallbacks = ["https://mirror.example.com/doc.json","doc.json"]`)
+}
+
+func TestMinifyConfig(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := MinifyConfig(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.MinifyConfig)
+
+	page, err := RenderHTML(MinifyConfig(true), Plugins([]string{"SomePlugin"}))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `SwaggerUIBundle({url:"doc.json",deepLinking:true,dom_id:"#swagger-ui",validatorUrl:null,`)
+	assert.Contains(t, string(page), `
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl,
+      SomePlugin
+    ],})`)
+	assert.NotContains(t, string(page), "docExpansion")
+	assert.NotContains(t, string(page), "persistAuthorization")
+	assert.NotContains(t, string(page), "layout")
+
+	page, err = RenderHTML(MinifyConfig(true), URLs([]URLsConfig{{URL: "doc.json", Name: "v1"}}))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `
+    ],
+layout:"StandaloneLayout"})`)
+
+	// the user supplied JavaScript is kept as is, whatever it looks like
+	interceptor := "(req) => { // note\n    docExpansion: \"list\",\n    return req\n  }"
+	page, err = RenderHTML(MinifyConfig(true), RequestInterceptor(interceptor),
+		UIConfig(map[string]string{"onComplete": "() => {\n    showExtensions: false,\n  }"}))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "validatorUrl:null,")
+	assert.Contains(t, string(page), "\n    requestInterceptor: "+interceptor+",\n    presets: [")
+	assert.Contains(t, string(page), "\n    ],\n    onComplete: () => {\n    showExtensions: false,\n  },\n})")
+
+	// nor is the output of a custom template
+	custom := template.Must(template.New("custom").Parse("<script>\n  SwaggerUIBundle({\n    docExpansion: \"list\",\n  })\n</script>\n"))
+	page, err = RenderHTML(MinifyConfig(true), Template(custom))
+	assert.NoError(t, err)
+	assert.Equal(t, "<script>\n  SwaggerUIBundle({\n    docExpansion: \"list\",\n  })\n</script>\n", string(page))
+}
+
+func TestNoIndex(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := NoIndex(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.NoIndex)
+
+	w := performRequest(http.MethodGet, "/index.html", Handler())
+	assert.Empty(t, w.Header().Get("X-Robots-Tag"))
+	assert.NotContains(t, w.Body.String(), `name="robots"`)
+
+	w = performRequest(http.MethodGet, "/index.html", Handler(NoIndex(true)))
+	assert.Equal(t, "noindex, nofollow", w.Header().Get("X-Robots-Tag"))
+	assert.Contains(t, w.Body.String(), `<meta name="robots" content="noindex, nofollow">`)
+}
+
+func TestHeadContent(t *testing.T) {
+	expected := `<link rel="preconnect" href="https://api.example.com">`
+	cfg := Config{}
+	configFunc := HeadContent(expected)
+	configFunc(&cfg)
+	assert.Equal(t, template.HTML(expected), cfg.HeadContent)
+
+	for _, renderer := range []string{"swagger", "redoc"} {
+		page, err := RenderHTML(Renderer(renderer), HeadContent(expected))
+		assert.NoError(t, err)
+		assert.Contains(t, string(page), "\n  "+expected+"\n</head>", renderer)
+	}
+}
+
+func TestHealthPath(t *testing.T) {
+	expected := "healthz"
+	cfg := Config{}
+	configFunc := HealthPath(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.HealthPath)
+
+	swag.Register("health", &mockedSwag{})
+
+	w := performRequest(http.MethodGet, "/swagger/healthz", Handler(InstanceName("health"), HealthPath("/healthz")))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"status":"ok"}`, w.Body.String())
+
+	var buf bytes.Buffer
+
+	w = performRequest(http.MethodGet, "/swagger/healthz", Handler(InstanceName("unregistered"), HealthPath("healthz"), Logger(log.New(&buf, "", 0))))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, `{"status":"unavailable"}`, w.Body.String())
+	assert.Equal(t, "httpSwagger: health check: no swag named \"unregistered\" was registered\n", buf.String())
+
+	swag.Register("TestHealthPathEmpty", stringSwag(" \n"))
+
+	w = performRequest(http.MethodGet, "/swagger/healthz", Handler(InstanceName("TestHealthPathEmpty"), HealthPath("healthz"), Logger(log.New(ioutil.Discard, "", 0))))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, `{"status":"unavailable"}`, w.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/healthz", Handler(InstanceName("health"))).Code)
+}
+
+func TestExposeConfigEndpoint(t *testing.T) {
+	cfg := Config{}
+	configFunc := ExposeConfigEndpoint(true)
+	configFunc(&cfg)
+	assert.True(t, cfg.ExposeConfigEndpoint)
+
+	h := Handler(
+		ExposeConfigEndpoint(true),
+		Title("Pets"),
+		Plugins([]string{"SomePlugin"}),
+		UIConfig(map[string]string{"showExtensions": "true"}),
+		BasicAuth("admin", "secret"),
+		OAuth2(func(c *OAuth2Config) {
+			c.ClientID = "pets-ui"
+			c.ClientSecret = "oauth-secret"
+			c.AdditionalQueryStringParams = map[string]string{"token": "query-secret"}
+		}),
+		Logger(log.New(ioutil.Discard, "", 0)),
+		SpecTransform(func(_ context.Context, raw []byte) ([]byte, error) { return raw, nil }),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger/config.json", nil)
+	req.SetBasicAuth("admin", "secret")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var resolved map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resolved))
+
+	assert.Equal(t, "Pets", resolved["Title"])
+	assert.Equal(t, "doc.json", resolved["URL"])
+	assert.Equal(t, "BaseLayout", resolved["Layout"])
+	assert.Equal(t, []interface{}{"SomePlugin"}, resolved["Plugins"])
+	assert.Equal(t, map[string]interface{}{"showExtensions": "true"}, resolved["UIConfig"])
+	assert.Equal(t, map[string]interface{}{"Username": "admin"}, resolved["BasicAuth"])
+	assert.Equal(t, true, resolved["ShowTopBar"])
+	assert.Equal(t, "", resolved["ValidatorURL"])
+	assert.NotContains(t, resolved, "Logger")
+	assert.NotContains(t, resolved, "SpecTransform")
+	assert.NotContains(t, resolved, "Template")
+	assert.Equal(t, map[string]interface{}{"clientId": "pets-ui"}, resolved["OAuth2Config"])
+	assert.NotContains(t, w.Body.String(), "secret")
+
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/config.json", Handler()).Code)
+
+	// the API definition served at config.json takes precedence
+	swag.Register("TestExposeConfigEndpoint", stringSwag(`{"swagger":"2.0"}`))
+	h = Handler(InstanceName("TestExposeConfigEndpoint"), ExposeConfigEndpoint(true), SpecPath("config.json"))
+	assert.Equal(t, `{"swagger":"2.0"}`, performRequest(http.MethodGet, "/swagger/config.json", h).Body.String())
+}
+
+func TestAuthorizationPersistKey(t *testing.T) {
+	expected := "orders-api-authorized"
+	cfg := Config{}
+	configFunc := AuthorizationPersistKey(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.AuthorizationPersistKey)
+
+	page, err := RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), "Storage.prototype")
+
+	page, err = RenderHTML(PersistAuthorization(true), AuthorizationPersistKey(expected))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `if (this === window.localStorage && key === "authorized") {
+        key = "orders-api-authorized"
+      }`)
+}
+
+func TestSpecFormat(t *testing.T) {
+	expected := "yaml"
+	cfg := Config{}
+	configFunc := SpecFormat(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.SpecFormat)
+}
+
+func TestSpecPath(t *testing.T) {
+	expected := "openapi.json"
+	cfg := Config{}
+	configFunc := SpecPath(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.SpecPath)
+
+	assert.Equal(t, "doc.yaml", newConfig(SpecFormat("yaml")).SpecPath)
+
+	swag.Register("spec_path", &mockedSwag{})
+
+	h := Handler(InstanceName("spec_path"), SpecPath("/openapi.json"))
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/swagger/openapi.json", h).Code)
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/doc.json", h).Code)
+	assert.Contains(t, performRequest(http.MethodGet, "/swagger/index.html", h).Body.String(), `url: "openapi.json",`)
+
+	h = Handler(InstanceName("spec_path"), SpecPath("openapi.json"), URL("https://example.org/docs/openapi.json"))
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/swagger/openapi.json", h).Code)
+	assert.Contains(t, performRequest(http.MethodGet, "/swagger/index.html", h).Body.String(), `url: "https:\/\/example.org\/docs\/openapi.json",`)
+}
+
+func TestReadOnly(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := ReadOnly(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.ReadOnly)
+}
+
+func TestCSPNonceFunc(t *testing.T) {
+	cfg := Config{}
+	configFunc := CSPNonceFunc(func(*http.Request) string { return "abc123" })
+	configFunc(&cfg)
+	assert.Equal(t, "abc123", cfg.CSPNonceFunc(nil))
+
+	h := Handler(CSPNonceFunc(func(*http.Request) string { return "abc123" }))
+
+	w := performRequest(http.MethodGet, "/index.html", h)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "script-src 'nonce-abc123'", w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, 3, strings.Count(w.Body.String(), `<script nonce="abc123"`))
+}
+
+func TestLocale(t *testing.T) {
+	expected := "pt-BR"
+	cfg := Config{}
+	configFunc := Locale(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.Locale)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler()).Body.String()
+	assert.Contains(t, body, `<html lang="en">`)
+	assert.NotContains(t, body, "MutationObserver")
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(Locale(expected))).Body.String()
+	assert.Contains(t, body, `<html lang="pt-BR">`)
+	assert.NotContains(t, body, "MutationObserver")
+}
+
+func TestLocaleData(t *testing.T) {
+	data := map[string]string{"pt": `{"Authorize": "Autorizar"}`, "de": `{"Authorize": "Autorisieren"}`}
+	cfg := Config{}
+	configFunc := LocaleData(data)
+	configFunc(&cfg)
+	assert.Equal(t, map[string]template.JS{"pt": `{"Authorize": "Autorizar"}`, "de": `{"Authorize": "Autorisieren"}`}, cfg.LocaleData)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler(LocaleData(data))).Body.String()
+	assert.NotContains(t, body, "MutationObserver")
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(LocaleData(data), Locale("de"), DomID("#docs"))).Body.String()
+	assert.Contains(t, body, `
+      () => ({
+        afterLoad: () => {
+          const translations = {"Authorize": "Autorisieren"}`)
+	assert.Contains(t, body, `.observe(document.getElementById("docs"), {childList: true, subtree: true, characterData: true})`)
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(LocaleData(data), Locale("pt-BR"))).Body.String()
+	assert.Contains(t, body, `const translations = {"Authorize": "Autorizar"}`)
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(LocaleData(data), Locale("fr"))).Body.String()
+	assert.NotContains(t, body, "MutationObserver")
+}
+
+func TestContentSecurityPolicy(t *testing.T) {
+	expected := "default-src 'self'; script-src 'nonce-{nonce}'"
+	cfg := Config{}
+	configFunc := ContentSecurityPolicy(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.ContentSecurityPolicy)
+
+	assert.Empty(t, performRequest(http.MethodGet, "/index.html", Handler(ContentSecurityPolicy(expected))).Header().Get("Content-Security-Policy"))
+
+	h := Handler(ContentSecurityPolicy(expected), CSPNonceFunc(func(*http.Request) string { return "abc123" }))
+	assert.Equal(t, "default-src 'self'; script-src 'nonce-abc123'", performRequest(http.MethodGet, "/index.html", h).Header().Get("Content-Security-Policy"))
+}
+
+func TestOfflineMode(t *testing.T) {
+	cfg := Config{CDNBaseURL: JSDelivrURL(SwaggerUIVersion)}
+	configFunc := OfflineMode()
+	configFunc(&cfg)
+	assert.True(t, cfg.InlineSpec)
+	assert.Empty(t, cfg.CDNBaseURL)
+	assert.Equal(t, "", *cfg.ValidatorURL)
+	assert.Equal(t, OfflineContentSecurityPolicy, cfg.ContentSecurityPolicy)
+	assert.NotNil(t, cfg.CSPNonceFunc)
+
+	swag.Register("TestOfflineMode", &mockedSwag{})
+	h := Handler(InstanceName("TestOfflineMode"), ValidatorURL("https://validator.swagger.io/validator"), OfflineMode())
+
+	w1 := performRequest(http.MethodGet, "/index.html", h)
+	w2 := performRequest(http.MethodGet, "/index.html", h)
+	assert.Equal(t, http.StatusOK, w1.Code)
+
+	csp := w1.Header().Get("Content-Security-Policy")
+	assert.Regexp(t, `^default-src 'self'; script-src 'nonce-[A-Za-z0-9+/]{22}=='; style-src 'self' 'unsafe-inline'; img-src 'self' data:$`, csp)
+	assert.NotEqual(t, csp, w2.Header().Get("Content-Security-Policy"))
+
+	body := w1.Body.String()
+	nonce := strings.TrimSuffix(strings.SplitN(csp, "'nonce-", 2)[1], "'; style-src 'self' 'unsafe-inline'; img-src 'self' data:")
+	assert.Equal(t, strings.Count(body, "<script"), strings.Count(body, `<script nonce="`+strings.ReplaceAll(nonce, "+", "&#43;")+`"`))
+	assert.Contains(t, body, `src="./swagger-ui-bundle.js"`)
+	assert.Contains(t, body, `spec: {`)
+	assert.NotContains(t, body, "validatorUrl: \"https")
+	assert.NotContains(t, body, "https://")
+
+	h = Handler(InstanceName("TestOfflineMode"), CSPNonceFunc(func(*http.Request) string { return "abc123" }), OfflineMode())
+	assert.Contains(t, performRequest(http.MethodGet, "/index.html", h).Header().Get("Content-Security-Policy"), "script-src 'nonce-abc123'")
+}
+
+func TestCacheControl(t *testing.T) {
+	expected := "public, max-age=86400"
+	cfg := Config{}
+	configFunc := CacheControl(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.CacheControl)
+
+	h := Handler(CacheControl(expected))
+
+	w1 := performRequest(http.MethodGet, "/swagger-ui.css", h)
+	assert.Equal(t, http.StatusOK, w1.Code)
+	assert.Equal(t, expected, w1.Header().Get("Cache-Control"))
+
+	etag := w1.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	r := httptest.NewRequest(http.MethodGet, "/swagger-ui.css", nil)
+	r.Header.Set("If-None-Match", etag)
+	w2 := httptest.NewRecorder()
+	h.ServeHTTP(w2, r)
+	assert.Equal(t, http.StatusNotModified, w2.Code)
+	assert.Equal(t, etag, w2.Header().Get("ETag"))
+}
+
+func TestGzip(t *testing.T) {
+	swag.Register("gzip", &mockedSwag{})
+
+	h := Handler(InstanceName("gzip"))
+
+	for _, target := range []string{"/swagger-ui-bundle.js", "/doc.json"} {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Header.Set("Accept-Encoding", "deflate, gzip;q=0.8")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+		zr, err := gzip.NewReader(w.Body)
+		assert.NoError(t, err)
+		body, err := ioutil.ReadAll(zr)
+		assert.NoError(t, err)
+
+		assert.Equal(t, performRequest(http.MethodGet, target, h).Body.String(), string(body))
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/swagger-ui-bundle.js", nil)
+	r.Header.Set("Accept-Encoding", "gzip;q=0")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+}
+
+func TestBrotli(t *testing.T) {
+	for _, name := range integrityAssets {
+		assert.NotEmpty(t, assets[name].brotlied, name)
+	}
+
+	h := Handler()
+
+	r := httptest.NewRequest(http.MethodGet, "/swagger-ui-bundle.js", nil)
+	r.Header.Set("Accept-Encoding", "gzip, deflate, br")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.True(t, strings.HasSuffix(w.Header().Get("ETag"), `-br"`))
+
+	body, err := ioutil.ReadAll(brotli.NewReader(w.Body))
+	assert.NoError(t, err)
+	assert.Equal(t, swaggerFiles.FileSwaggerUIBundleJs, body)
+
+	r = httptest.NewRequest(http.MethodGet, "/swagger-ui-bundle.js", nil)
+	r.Header.Set("Accept-Encoding", "gzip, br;q=0")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+	r = httptest.NewRequest(http.MethodGet, "/swagger-ui-bundle.js", nil)
+	r.Header.Set("Accept-Encoding", "br")
+	r.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTitle(t *testing.T) {
+	expected := "Petstore API"
+	cfg := Config{}
+	configFunc := Title(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.Title)
+
+	w := performRequest(http.MethodGet, "/index.html", Handler(Title("<Petstore> & Co")))
+	assert.Contains(t, w.Body.String(), "<title>&lt;Petstore&gt; &amp; Co</title>")
+}
+
+func TestFaviconURL(t *testing.T) {
+	expected := "https://example.org/favicon.ico"
+	cfg := Config{}
+	configFunc := FaviconURL(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.FaviconURL)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler(FaviconURL(expected))).Body.String()
+	assert.Contains(t, body, `<link rel="icon" href="https://example.org/favicon.ico" />`)
+	assert.NotContains(t, body, "favicon-16x16.png")
+}
+
+func TestCustomCSS(t *testing.T) {
+	expected := ".topbar { display: none; }"
+	cfg := Config{}
+	configFunc := CustomCSS(expected)
+	configFunc(&cfg)
+	assert.Equal(t, template.CSS(expected), cfg.CustomCSS)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler(CustomCSS(expected))).Body.String()
+	assert.Contains(t, body, "<style>\n    .topbar { display: none; }\n  </style>\n</head>")
+}
+
+func TestOAuth2RedirectURL(t *testing.T) {
+	expected := "https://example.org/docs/oauth2-redirect.html"
+	cfg := Config{}
+	configFunc := OAuth2RedirectURL(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.OAuth2RedirectURL)
+
+	h := Handler(OAuth2RedirectURL(expected))
+
+	body := performRequest(http.MethodGet, "/index.html", h).Body.String()
+	assert.Contains(t, body, `oauth2RedirectUrl: "https:\/\/example.org\/docs\/oauth2-redirect.html",`)
+
+	w := performRequest(http.MethodGet, "/oauth2-redirect.html", h)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+}
+
+func TestOAuth2(t *testing.T) {
+	cfg := Config{}
+	configFunc := OAuth2(func(c *OAuth2Config) {
+		c.ClientID = "client-id"
+		c.Scopes = []string{"read", "write"}
+	})
+	configFunc(&cfg)
+	assert.Equal(t, &OAuth2Config{ClientID: "client-id", Scopes: []string{"read", "write"}}, cfg.OAuth2Config)
+
+	OAuth2(func(c *OAuth2Config) {
+		c.UsePkceWithAuthorizationCodeGrant = true
+	})(&cfg)
+	assert.Equal(t, "client-id", cfg.OAuth2Config.ClientID)
+	assert.True(t, cfg.OAuth2Config.UsePkceWithAuthorizationCodeGrant)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler(configFunc)).Body.String()
+	assert.Contains(t, body, `ui.initOAuth({"clientId":"client-id","scopes":["read","write"]})`)
+
+	body = performRequest(http.MethodGet, "/index.html", Handler()).Body.String()
+	assert.NotContains(t, body, "initOAuth")
+}
+
+func TestLayout(t *testing.T) {
+	expected := "StandaloneLayout"
+	cfg := Config{}
+	configFunc := Layout(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.Layout)
+
+	assert.Equal(t, "BaseLayout", newConfig().Layout)
+	assert.Equal(t, "StandaloneLayout", newConfig(URLs([]URLsConfig{{URL: "doc.json", Name: "v1"}})).Layout)
+	assert.Equal(t, "BaseLayout", newConfig(URLs([]URLsConfig{{URL: "doc.json", Name: "v1"}}), Layout("BaseLayout")).Layout)
+
+	_, err := HandlerWithError(Layout("CustomLayout"), StrictValidation(true))
+	assert.Error(t, err)
+	assert.Panics(t, func() { Handler(Layout("CustomLayout"), StrictValidation(true)) })
+}
+
+func TestURLs(t *testing.T) {
+	expected := []URLsConfig{{URL: "doc.json", Name: "v1"}}
+	cfg := Config{}
+	configFunc := URLs(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.URLs)
+}
+
+func TestFilter(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := Filter(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.Filter)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler(Filter(true), FilterExpression("pet"))).Body.String()
+	assert.Contains(t, body, `filter: "pet",`)
+	assert.NotContains(t, body, `filter: true,`)
+}
+
+func TestFilterExpression(t *testing.T) {
+	expected := "pet"
+	cfg := Config{}
+	configFunc := FilterExpression(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.FilterExpression)
+}
+
+func TestMaxDisplayedTags(t *testing.T) {
+	expected := 10
+	cfg := Config{}
+	configFunc := MaxDisplayedTags(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.MaxDisplayedTags)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler(MaxDisplayedTags(0))).Body.String()
+	assert.NotContains(t, body, "maxDisplayedTags")
+}
+
+func TestInlineSpec(t *testing.T) {
+	cfg := Config{}
+	configFunc := InlineSpec(true)
+	configFunc(&cfg)
+	assert.True(t, cfg.InlineSpec)
+
+	doc := `{"info":{"title":"</script><script>alert(1)</script>","version":"1"},"paths":{"/b":{},"/a":{}}}`
+	h := Handler(InlineSpec(true), SpecProvider(func(context.Context) ([]byte, string, error) {
+		return []byte(doc), "application/json", nil
+	}))
+
+	body := performRequest(http.MethodGet, "/index.html", h).Body.String()
+	assert.Contains(t, body, `
+    spec: {"info":{"title":"\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e","version":"1"},"paths":{"/b":{},"/a":{}}},
+    deepLinking:`)
+	assert.NotContains(t, body, `url: "doc.json"`)
+	assert.NotContains(t, body, "<script>alert(1)")
+
+	doc = `{"info":{"version":"2"}}`
+	assert.Contains(t, performRequest(http.MethodGet, "/index.html", h).Body.String(), `spec: {"info":{"version":"2"}},`)
+
+	page, err := RenderHTML(InlineSpec(true), SpecFS(fstest.MapFS{"doc.yaml": {Data: []byte("openapi: 3.0.3\ninfo:\n  version: \"3\"\n")}}, "doc.yaml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `spec: {"info":{"version":"3"},"openapi":"3.0.3"},`)
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(InlineSpec(true), Renderer("redoc"), SpecProvider(func(context.Context) ([]byte, string, error) {
+		return []byte(`{"openapi":"3.0.3"}`), "application/json", nil
+	}))).Body.String()
+	assert.Contains(t, body, `Redoc.init({"openapi":"3.0.3"}, {}`)
+
+	var buf bytes.Buffer
+	h = Handler(InlineSpec(true), Logger(log.New(&buf, "", 0)), SpecProvider(func(context.Context) ([]byte, string, error) {
+		return nil, "", errors.New("unavailable")
+	}))
+	assert.Equal(t, http.StatusInternalServerError, performRequest(http.MethodGet, "/index.html", h).Code)
+	assert.Equal(t, "httpSwagger: rendering index page: unavailable\n", buf.String())
+}
+
+func TestRequestTimeoutMs(t *testing.T) {
+	expected := 5000
+	cfg := Config{}
+	configFunc := RequestTimeoutMs(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.RequestTimeoutMs)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler()).Body.String()
+	assert.NotContains(t, body, "AbortController")
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(RequestTimeoutMs(expected))).Body.String()
+	assert.Contains(t, body, `
+    requestInterceptor: async (req) => {
+      const controller = new AbortController()
+      setTimeout(() => controller.abort(new Error("The request timed out after 5000 ms")),  5000 )
+      req.signal = controller.signal
+      return req
+    },`)
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(RequestTimeoutMs(expected), RequestInterceptor(`(req) => req`))).Body.String()
+	assert.Contains(t, body, `
+    requestInterceptor: async (req) => {
+      req = await ((req) => req)(req)
+      const controller = new AbortController()`)
+	assert.Equal(t, 1, strings.Count(body, "requestInterceptor:"))
+}
+
+func TestDevReload(t *testing.T) {
+	cfg := Config{}
+	configFunc := DevReload(true)
+	configFunc(&cfg)
+	assert.True(t, cfg.DevReload)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler()).Body.String()
+	assert.NotContains(t, body, "window.location.reload()")
+	assert.Empty(t, performRequest(http.MethodGet, "/doc.json", Handler()).Header().Get("ETag"))
+
+	doc := `{"info":{"version":"1"}}`
+	h := Handler(DevReload(true), SpecProvider(func(context.Context) ([]byte, string, error) {
+		return []byte(doc), "application/json", nil
+	}))
+
+	body = performRequest(http.MethodGet, "/index.html", h).Body.String()
+	assert.Contains(t, body, `fetch("doc.json", {method: "HEAD", cache: "no-store"})`)
+	assert.Contains(t, body, "window.location.reload()")
+
+	etag := performRequest(http.MethodHead, "/doc.json", h).Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`))
+	assert.Equal(t, etag, performRequest(http.MethodGet, "/doc.json", h).Header().Get("ETag"))
+
+	r := httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	doc = `{"info":{"version":"2"}}`
+	assert.NotEqual(t, etag, performRequest(http.MethodHead, "/doc.json", h).Header().Get("ETag"))
+}
+
+func TestOperationsSorter(t *testing.T) {
+	cfg := Config{}
+	configFunc := OperationsSorter("method")
+	configFunc(&cfg)
+	assert.Equal(t, template.JS(`"method"`), cfg.OperationsSorter)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler()).Body.String()
+	assert.NotContains(t, body, "operationsSorter")
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(OperationsSorter("alpha"))).Body.String()
+	assert.Contains(t, body, `
+    operationsSorter: "alpha",`)
+
+	fn := `(a, b) => a.get("path").localeCompare(b.get("path"))`
+	body = performRequest(http.MethodGet, "/index.html", Handler(OperationsSorter(fn))).Body.String()
+	assert.Contains(t, body, "\n    operationsSorter: "+fn+",")
+}
+
+func TestTagsSorter(t *testing.T) {
+	cfg := Config{}
+	configFunc := TagsSorter("alpha")
+	configFunc(&cfg)
+	assert.Equal(t, template.JS(`"alpha"`), cfg.TagsSorter)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler()).Body.String()
+	assert.NotContains(t, body, "tagsSorter")
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(TagsSorter("alpha"))).Body.String()
+	assert.Contains(t, body, `
+    tagsSorter: "alpha",`)
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(TagsSorter("(a, b) => b.localeCompare(a)"))).Body.String()
+	assert.Contains(t, body, `
+    tagsSorter: (a, b) => b.localeCompare(a),`)
+}
+
+func TestDefaultModelsExpandDepth(t *testing.T) {
+	cfg := Config{}
+	configFunc := DefaultModelsExpandDepth(0)
+	configFunc(&cfg)
+	assert.Equal(t, 0, *cfg.DefaultModelsExpandDepth)
+
+	page, err := RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), "defaultModelsExpandDepth")
+
+	page, err = RenderHTML(DefaultModelsExpandDepth(0))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "defaultModelsExpandDepth:  0 ,")
+}
+
+func TestDefaultModelExpandDepth(t *testing.T) {
+	cfg := Config{}
+	configFunc := DefaultModelExpandDepth(3)
+	configFunc(&cfg)
+	assert.Equal(t, 3, *cfg.DefaultModelExpandDepth)
+
+	page, err := RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), "defaultModelExpandDepth")
+
+	page, err = RenderHTML(DisableDefaultModelExpansion())
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "defaultModelExpandDepth:  0 ,")
+}
+
+func TestModelRendering(t *testing.T) {
+	cfg := Config{}
+	configFunc := ModelRendering("schema", 2)
+	configFunc(&cfg)
+	assert.Equal(t, "model", cfg.DefaultModelRendering)
+	assert.Equal(t, 2, *cfg.DefaultModelExpandDepth)
+
+	page, err := RenderHTML(ModelRendering("example", 0))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `
+    defaultModelRendering: "example",`)
+	assert.Contains(t, string(page), "defaultModelExpandDepth:  0 ,")
+
+	_, err = HandlerWithError(StrictValidation(true), SpecFS(fstest.MapFS{}, "doc.json"), ModelRendering("table", 1))
+	assert.EqualError(t, err, `httpSwagger: invalid default model rendering "table"`)
+}
+
+func TestHideModels(t *testing.T) {
+	cfg := Config{}
+	configFunc := HideModels()
+	configFunc(&cfg)
+	assert.Equal(t, -1, *cfg.DefaultModelsExpandDepth)
+
+	page, err := RenderHTML(HideModels())
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "defaultModelsExpandDepth:  -1 ,")
+
+	page, err = RenderHTML(HideModels(), MinifyConfig(true))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "defaultModelsExpandDepth:-1,")
+}
+
+func TestShowModels(t *testing.T) {
+	cfg := Config{ShowModels: true}
+	configFunc := ShowModels(false)
+	configFunc(&cfg)
+	assert.False(t, cfg.ShowModels)
+
+	assert.Nil(t, newConfig().DefaultModelsExpandDepth)
+
+	page, err := RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), "defaultModelsExpandDepth")
+	assert.NotContains(t, string(page), ".swagger-ui .models")
+
+	page, err = RenderHTML(ShowModels(false), DefaultModelsExpandDepth(2))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "defaultModelsExpandDepth:  -1 ,")
+	assert.Contains(t, string(page), `
+  <style>
+    .swagger-ui .models { display: none; }
+  </style>`)
+}
+
+func TestShowDownloadButton(t *testing.T) {
+	cfg := Config{ShowDownloadButton: true}
+	configFunc := ShowDownloadButton(false)
+	configFunc(&cfg)
+	assert.False(t, cfg.ShowDownloadButton)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler()).Body.String()
+	assert.NotContains(t, body, "InfoUrl")
+	assert.NotContains(t, body, ".download-url-wrapper")
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(ShowDownloadButton(false))).Body.String()
+	assert.Contains(t, body, `
+  <style>
+    .swagger-ui .download-url-wrapper { display: none; }
+  </style>`)
+	assert.Contains(t, body, `
+      SwaggerUIBundle.plugins.DownloadUrl,
+      () => ({
+        wrapComponents: {
+          InfoUrl: () => () => null
+        }
+      })
+    ],`)
+}
+
+func TestSyntaxHighlight(t *testing.T) {
+	cfg := Config{}
+	configFunc := SyntaxHighlight(false)
+	configFunc(&cfg)
+	assert.Equal(t, &SyntaxHighlightConfig{Activate: false}, cfg.SyntaxHighlight)
+
+	SyntaxHighlightTheme("nord")(&cfg)
+	assert.Equal(t, &SyntaxHighlightConfig{Activate: false, Theme: "nord"}, cfg.SyntaxHighlight)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler(SyntaxHighlight(false))).Body.String()
+	assert.Contains(t, body, "syntaxHighlight: false,")
+
+	body = performRequest(http.MethodGet, "/index.html", Handler()).Body.String()
+	assert.NotContains(t, body, "syntaxHighlight")
+}
+
+func TestSyntaxHighlightTheme(t *testing.T) {
+	cfg := Config{}
+	configFunc := SyntaxHighlightTheme("obsidian")
+	configFunc(&cfg)
+	assert.Equal(t, &SyntaxHighlightConfig{Activate: true, Theme: "obsidian"}, cfg.SyntaxHighlight)
+
+	_, err := HandlerWithError(SyntaxHighlightTheme("solarized"), StrictValidation(true))
+	assert.Error(t, err)
+}
+
+func TestDefaultModelRendering(t *testing.T) {
+	expected := "model"
+	cfg := Config{}
+	configFunc := DefaultModelRendering(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.DefaultModelRendering)
+
+	body := performRequest(http.MethodGet, "/index.html", Handler(DefaultModelRendering(expected))).Body.String()
+	assert.Contains(t, body, `defaultModelRendering: "model",`)
+}
+
+func TestStrictValidation(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := StrictValidation(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.StrictValidation)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	h := NewHandler(DocExpansion("expanded"), DefaultModelRendering("schema"), Layout("CustomLayout"))
+	assert.Equal(t, "list", h.Config().DocExpansion)
+	assert.Equal(t, "", h.Config().DefaultModelRendering)
+	assert.Equal(t, "BaseLayout", h.Config().Layout)
+	assert.Contains(t, buf.String(), `httpSwagger: invalid doc expansion "expanded", using "list"`)
+
+	_, err := HandlerWithError(DocExpansion("expanded"), StrictValidation(true))
+	assert.EqualError(t, err, `httpSwagger: invalid doc expansion "expanded"`)
+
+	_, err = HandlerWithError(DefaultModelRendering("schema"), StrictValidation(true))
+	assert.EqualError(t, err, `httpSwagger: invalid default model rendering "schema"`)
+}
+
+func TestUIConfigKeys(t *testing.T) {
+	_, err := HandlerWithError(StrictValidation(true), SpecFS(fstest.MapFS{}, "doc.json"), UIConfig(map[string]string{
+		"showExtensions": "true",
+		`"x-tagGroups"`:  "[]",
+	}))
+	assert.NoError(t, err)
+
+	_, err = HandlerWithError(StrictValidation(true), SpecFS(fstest.MapFS{}, "doc.json"), UIConfig(map[string]string{"showExtension": "true"}))
+	assert.EqualError(t, err, `httpSwagger: unknown UIConfig key "showExtension"`)
+
+	var buf bytes.Buffer
+	cfg := newConfig(Logger(log.New(&buf, "", 0)), UIConfig(map[string]string{"showExtension": "true"}))
+	assert.NoError(t, cfg.validate())
+	assert.Equal(t, "httpSwagger: unknown UIConfig key \"showExtension\"\n", buf.String())
+	assert.Equal(t, template.JS("true"), cfg.UIConfig["showExtension"])
+}
+
+func TestOpenAPI31(t *testing.T) {
+	doc := `{
+  "openapi": "3.1.0",
+  "$schema": "https://spec.openapis.org/oas/3.1/dialect/base",
+  "info": {"title": "Pets", "version": "1.0"},
+  "paths": {
+    "/pets": {
+      "get": {
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {"application/json": {"schema": {"type": ["string", "null"]}}}
+          }
+        }
+      }
+    }
+  }
+}`
+	swag.Register("TestOpenAPI31", stringSwag(doc))
+
+	var buf bytes.Buffer
+	h := Handler(InstanceName("TestOpenAPI31"), Logger(log.New(&buf, "", 0)))
+	assert.Equal(t, "httpSwagger: the API definition is OpenAPI 3.1.0, which Swagger UI 4.11.0 does not support, load a 5.x release with CDN\n", buf.String())
+
+	w := performRequest(http.MethodGet, "/doc.json", h)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, doc, w.Body.String())
+
+	body := performRequest(http.MethodGet, "/doc.yaml", h).Body.String()
+	assert.Contains(t, body, "$schema: https://spec.openapis.org/oas/3.1/dialect/base\n")
+	assert.Contains(t, body, "type:\n                - string\n                - \"null\"\n")
+
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/index.html", h).Code)
+
+	body = performRequest(http.MethodGet, "/index.html", Handler(InstanceName("TestOpenAPI31"), StaticRender(true))).Body.String()
+	assert.Contains(t, body, "<tr><td>200</td><td>string | null</td><td>OK</td></tr>")
+
+	buf.Reset()
+	Handler(InstanceName("TestOpenAPI31"), Logger(log.New(&buf, "", 0)), CDN(JSDelivrURL("5.17.14")))
+	Handler(InstanceName("TestOpenAPI31"), Logger(log.New(&buf, "", 0)), Renderer("redoc"))
+	Handler(Logger(log.New(&buf, "", 0)))
+	assert.Empty(t, buf.String())
+}
+
+func TestOverrideSpecServers(t *testing.T) {
+	expected := []string{"/api/v1", "https://eu.example.com/api/v1"}
+	cfg := Config{}
+	configFunc := OverrideSpecServers(expected...)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.OverrideSpecServers)
+
+	swag.Register("TestOverrideSpecServers", stringSwag(`{"openapi":"3.0.3","info":{"title":"<Pets>"},"servers":[{"url":"http://localhost:8080"}],"paths":{}}`))
+
+	h := Handler(InstanceName("TestOverrideSpecServers"), OverrideSpecServers(expected...))
+	r := httptest.NewRequest(http.MethodGet, "https://docs.example.com/doc.json", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"info":{"title":"<Pets>"},"openapi":"3.0.3","paths":{},"servers":[{"url":"https://docs.example.com/api/v1"},{"url":"https://eu.example.com/api/v1"}]}`, w.Body.String())
+
+	h = Handler(InstanceName("TestOverrideSpecServers"), OverrideSpecServers("/api"), TrustForwardedHeaders(true))
+	r = httptest.NewRequest(http.MethodGet, "/doc.yaml", nil)
+	r.Header.Set("X-Forwarded-Proto", "https")
+	r.Header.Set("X-Forwarded-Host", "public.example.com")
+	r.Header.Set("X-Forwarded-Prefix", "/petstore/")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, `openapi: 3.0.3
+info:
+  title: <Pets>
+servers:
+- url: https://public.example.com/petstore/api
+paths: {}
+`, w.Body.String())
+
+	swag.Register("TestOverrideSpecServers2", &mockedSwag{})
+
+	h = Handler(InstanceName("TestOverrideSpecServers2"), OverrideSpecServers("/v3"))
+	body := performRequest(http.MethodGet, "/doc.json", h).Body.String()
+	assert.Contains(t, body, `"host":"example.com"`)
+	assert.Contains(t, body, `"basePath":"/v3"`)
+	assert.Contains(t, body, `"schemes":["http"]`)
+
+	h = Handler(SpecProvider(func(context.Context) ([]byte, string, error) {
+		return []byte("not a definition"), "application/json", nil
+	}), OverrideSpecServers("/v3"))
+	assert.Equal(t, http.StatusInternalServerError, performRequest(http.MethodGet, "/doc.json", h).Code)
+}
+
+func TestServerVariables(t *testing.T) {
+	expected := map[string]string{"region": "eu", "version": "v2"}
+	cfg := Config{}
+	configFunc := ServerVariables(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.ServerVariables)
+
+	swag.Register("TestServerVariables", stringSwag(`{
+  "openapi": "3.0.3",
+  "servers": [
+    {"url": "https://{region}.api.example.com/{version}", "variables": {"region": {"default": "us", "enum": ["us", "ap"]}}},
+    {"url": "https://api.example.com"}
+  ]
+}`))
+
+	h := Handler(InstanceName("TestServerVariables"), ServerVariables(expected))
+	assert.Equal(t, `{"openapi":"3.0.3","servers":[{"url":"https://{region}.api.example.com/{version}","variables":{"region":{"default":"eu","enum":["us","ap","eu"]},"version":{"default":"v2"}}},{"url":"https://api.example.com"}]}`,
+		performRequest(http.MethodGet, "/doc.json", h).Body.String())
+
+	h = Handler(InstanceName("TestServerVariables"), ServerVariables(expected), OverrideSpecServers("https://{region}.example.org"))
+	assert.Equal(t, `openapi: 3.0.3
+servers:
+- url: https://{region}.example.org
+  variables:
+    region:
+      default: eu
+`, performRequest(http.MethodGet, "/doc.yaml", h).Body.String())
+}
+
+func TestBasePath(t *testing.T) {
+	expected := "/docs/swagger/"
+	cfg := Config{}
+	configFunc := BasePath(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.BasePath)
+
+	body := performRequest(http.MethodGet, "/swagger/index.html", Handler(BasePath("/docs/swagger"))).Body.String()
+	assert.Contains(t, body, `href="/docs/swagger/swagger-ui.css"`)
+	assert.Contains(t, body, `src="/docs/swagger/swagger-ui-bundle.js"`)
+	assert.Contains(t, body, `url: "\/docs\/swagger\/doc.json",`)
+
+	body = performRequest(http.MethodGet, "/swagger/index.html", Handler(BasePath("/docs/swagger"), URL("https://example.org/doc.json"))).Body.String()
+	assert.Contains(t, body, `url: "https:\/\/example.org\/doc.json",`)
+
+	w := performRequest(http.MethodGet, "/swagger/", Handler(BasePath("/docs/swagger/")))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/docs/swagger/index.html", w.Header().Get("Location"))
+
+}
+
+func TestTrustForwardedHeaders(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := TrustForwardedHeaders(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.TrustForwardedHeaders)
+
+	render := func(h http.Handler, headers map[string]string) string {
+		r := httptest.NewRequest(http.MethodGet, "/swagger/index.html", nil)
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		return w.Body.String()
+	}
+
+	body := render(Handler(TrustForwardedHeaders(true)), map[string]string{"X-Forwarded-Prefix": "/api/"})
+	assert.Contains(t, body, `href="/api/swagger/swagger-ui.css"`)
+	assert.Contains(t, body, `url: "\/api\/swagger\/doc.json",`)
+	assert.Contains(t, body, `oauth2RedirectUrl: window.location.origin`)
+
+	body = render(Handler(TrustForwardedHeaders(true)), map[string]string{
+		"X-Forwarded-Proto":  "https, http",
+		"X-Forwarded-Host":   "docs.example.org",
+		"X-Forwarded-Prefix": "/api",
+	})
+	assert.Contains(t, body, `url: "https:\/\/docs.example.org\/api\/swagger\/doc.json",`)
+	assert.Contains(t, body, `oauth2RedirectUrl: "https:\/\/docs.example.org\/api\/swagger\/oauth2-redirect.html",`)
+
+	body = render(Handler(), map[string]string{"X-Forwarded-Host": "evil.example.org"})
+	assert.Contains(t, body, `url: "doc.json",`)
+}
+
+func TestRenderHTML(t *testing.T) {
+	page, err := RenderHTML(DocExpansion("none"), Title("Petstore"), Plugins([]string{"SomePlugin"}))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "<title>Petstore</title>")
+	assert.Contains(t, string(page), `docExpansion: "none",`)
+	assert.Contains(t, string(page), "SwaggerUIBundle.plugins.DownloadUrl,\n      SomePlugin\n")
+
+	// the page is the one served by the handler
+	page, err = RenderHTML(Title("Petstore"))
+	assert.NoError(t, err)
+	assert.Equal(t, string(page), performRequest(http.MethodGet, "/index.html", NewHandler(Title("Petstore"))).Body.String())
+
+	// the unset options get their defaults
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	page, err = RenderHTML(func(c *Config) { c.Title = "x" })
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `url: "doc.json",`)
+	assert.Contains(t, string(page), `deepLinking:  true ,`)
+	assert.NotContains(t, string(page), "display: none")
+	assert.NotContains(t, string(page), "showMutatedRequest")
+	assert.Empty(t, buf.String())
+
+	// invalid values are reset as by the handler
+	page, err = RenderHTML(DocExpansion("bogus"), Logger(log.New(ioutil.Discard, "", 0)))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `docExpansion: "list",`)
+
+	_, err = RenderHTML(DocExpansion("bogus"), StrictValidation(true))
+	assert.Error(t, err)
+}
+
+func TestShowTopBar(t *testing.T) {
+	expected := false
+	cfg := Config{}
+	configFunc := ShowTopBar(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.ShowTopBar)
+
+	config := newConfig(URLs([]URLsConfig{{URL: "doc.json", Name: "v1"}}), ShowTopBar(false))
+	assert.Equal(t, "BaseLayout", config.Layout)
+
+	page, err := RenderHTML(URLs([]URLsConfig{{URL: "doc.json", Name: "v1"}}), ShowTopBar(false))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), ".topbar { display: none; }")
+
+	page, err = RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), ".topbar")
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.New(&buf, "", 0)
+
+	cfg := Config{}
+	configFunc := Logger(logger)
+	configFunc(&cfg)
+	assert.Equal(t, logger, cfg.Logger)
+
+	h := Handler(Logger(logger), InstanceName("unregistered"))
+	assert.Equal(t, http.StatusInternalServerError, performRequest(http.MethodGet, "/doc.json", h).Code)
+	assert.Equal(t, "httpSwagger: reading API definition: no swag named \"unregistered\" was registered\n", buf.String())
+
+	buf.Reset()
+	Handler(Logger(logger), DocExpansion("expanded"))
+	assert.Equal(t, "httpSwagger: invalid doc expansion \"expanded\", using \"list\"\n", buf.String())
+}
+
+func TestMetricsHook(t *testing.T) {
+	events := map[string]int{}
+	hook := func(event string) {
+		events[event]++
+	}
+
+	cfg := Config{}
+	configFunc := MetricsHook(hook)
+	configFunc(&cfg)
+	cfg.MetricsHook("ui")
+	assert.Equal(t, 1, events["ui"])
+
+	swag.Register("metrics", &mockedSwag{})
+	h := Handler(InstanceName("metrics"), MetricsHook(hook))
+
+	performRequest(http.MethodGet, "/index.html", h)
+	performRequest(http.MethodGet, "/doc.json", h)
+	performRequest(http.MethodGet, "/doc.json", h)
+	performRequest(http.MethodGet, "/swagger-ui.css", h)
+	performRequest(http.MethodGet, "/notfound", h)
+	assert.Equal(t, map[string]int{"ui": 2, "spec": 2, "asset": 1}, events)
+}
+
+func TestValidatorURL(t *testing.T) {
+	expected := "https://validator.example.org"
+	cfg := Config{}
+	configFunc := ValidatorURL(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, *cfg.ValidatorURL)
+
+	DisableValidator()(&cfg)
+	assert.Equal(t, "", *cfg.ValidatorURL)
+
+	page, err := RenderHTML(ValidatorURL(expected))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `validatorUrl: "https://validator.example.org",`)
+
+	page, err = RenderHTML()
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `validatorUrl: null,`)
+
+	page, err = RenderHTML(func(c *Config) { c.ValidatorURL = nil })
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), `validatorUrl`)
+}
+
+func TestShowMutatedRequest(t *testing.T) {
+	expected := false
+	cfg := Config{}
+	configFunc := ShowMutatedRequest(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.ShowMutatedRequest)
+
+	page, err := RenderHTML(ShowMutatedRequest(false))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `showMutatedRequest: false,`)
+
+	page, err = RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), `showMutatedRequest`)
+}
+
+func TestTryItOutEnabled(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := TryItOutEnabled(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.TryItOutEnabled)
+}
+
+func TestPresets(t *testing.T) {
+	cfg := Config{}
+	configFunc := Presets([]string{"SwaggerUIBundle.presets.apis", "CustomPreset"})
+	configFunc(&cfg)
+	assert.Equal(t, []template.JS{"SwaggerUIBundle.presets.apis", "CustomPreset"}, cfg.Presets)
 
-	return w
+	page, err := RenderHTML(Presets([]string{"SwaggerUIBundle.presets.apis", "CustomPreset"}))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      CustomPreset
+    ],`)
 }
 
-func TestURL(t *testing.T) {
-	expected := "https://github.com/swaggo/http-swagger"
+func TestCDN(t *testing.T) {
+	expected := JSDelivrURL(SwaggerUIVersion)
 	cfg := Config{}
-	configFunc := URL(expected)
+	configFunc := CDN(expected)
 	configFunc(&cfg)
-	assert.Equal(t, expected, cfg.URL)
+	assert.Equal(t, expected, cfg.CDNBaseURL)
+	assert.Equal(t, "https://cdn.jsdelivr.net/npm/swagger-ui-dist@4.11.0", expected)
+	assert.Contains(t, string(swaggerFiles.FileSwaggerUIBundleJs), `"`+SwaggerUIVersion+`"`)
+
+	router := http.NewServeMux()
+	router.Handle("/swagger/", Handler(CDN(expected+"/"), BasePath("/docs/swagger/")))
+
+	body := performRequest(http.MethodGet, "/swagger/index.html", router).Body.String()
+	assert.Contains(t, body, `href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@4.11.0/swagger-ui.css"`)
+	assert.Contains(t, body, `src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@4.11.0/swagger-ui-bundle.js"`)
+	assert.Contains(t, body, `url: "\/docs\/swagger\/doc.json",`)
+
+	assert.Equal(t, http.StatusNotFound, performRequest(http.MethodGet, "/swagger/swagger-ui-bundle.js", router).Code)
+	assert.Equal(t, http.StatusOK, performRequest(http.MethodGet, "/swagger/oauth2-redirect.html", router).Code)
 }
 
-func TestDeepLinking(t *testing.T) {
+func TestAssetIntegrity(t *testing.T) {
+	expected := map[string]string{"swagger-ui-bundle.js": "sha384-abc"}
+	cfg := Config{}
+	configFunc := AssetIntegrity(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.AssetIntegrity)
+
+	sum := sha512.Sum384(swaggerFiles.FileSwaggerUIBundleJs)
+	assert.Equal(t, "sha384-"+base64.StdEncoding.EncodeToString(sum[:]), assets["swagger-ui-bundle.js"].Integrity())
+
+	page, err := RenderHTML(CDN(JSDelivrURL(SwaggerUIVersion)), AssetIntegrity(expected))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `swagger-ui-bundle.js" integrity="sha384-abc" crossorigin="anonymous">`)
+	assert.Contains(t, string(page), `swagger-ui-standalone-preset.js">`)
+
+	page, err = RenderHTML(AssetFS(fstest.MapFS{}))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), "integrity")
+}
+
+func TestStylesheets(t *testing.T) {
+	expected := []string{"https://fonts.googleapis.com/css2?family=Inter", "/static/brand.css"}
+	cfg := Config{}
+	configFunc := Stylesheets(expected...)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.Stylesheets)
+
+	for _, renderer := range []string{"swagger", "redoc"} {
+		page, err := RenderHTML(Renderer(renderer), Stylesheets(expected...))
+		assert.NoError(t, err)
+		assert.Contains(t, string(page), `
+  <link rel="stylesheet" href="https://fonts.googleapis.com/css2?family=Inter">
+  <link rel="stylesheet" href="/static/brand.css">`, renderer)
+	}
+}
+
+func TestScripts(t *testing.T) {
+	expected := []string{"https://cdn.example.com/plugin.js"}
+	cfg := Config{}
+	configFunc := Scripts(expected...)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.Scripts)
+
+	h := Handler(Scripts(expected...), CSPNonceFunc(func(*http.Request) string { return "abc123" }))
+	body := performRequest(http.MethodGet, "/index.html", h).Body.String()
+	assert.Contains(t, body, `swagger-ui-standalone-preset.js" integrity="`)
+	assert.Contains(t, body, `
+<script nonce="abc123" src="https://cdn.example.com/plugin.js"> </script>
+<script nonce="abc123">`)
+}
+
+func TestStaticRender(t *testing.T) {
+	cfg := Config{}
+	configFunc := StaticRender(true)
+	configFunc(&cfg)
+	assert.True(t, cfg.StaticRender)
+
+	swagger2 := `{
+  "swagger": "2.0",
+  "info": {"title": "Pet <Store>", "version": "1.0"},
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"name": "id", "in": "path", "type": "integer", "format": "int64", "required": true}],
+      "get": {
+        "operationId": "getPet",
+        "summary": "Find a pet",
+        "tags": ["pets"],
+        "responses": {"200": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}}}
+      },
+      "delete": {"deprecated": true, "responses": {"204": {"description": "Deleted"}}}
+    }
+  },
+  "definitions": {
+    "Pet": {"required": ["name"], "properties": {"name": {"type": "string"}, "tags": {"type": "array", "items": {"type": "string"}}}}
+  }
+}`
+
+	h := Handler(StaticRender(true), SpecProvider(func(context.Context) ([]byte, string, error) {
+		return []byte(swagger2), "application/json", nil
+	}))
+
+	w := performRequest(http.MethodGet, "/index.html", h)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.NotContains(t, body, "<script")
+	assert.Contains(t, body, "<title>Pet &lt;Store&gt;</title>")
+	assert.Contains(t, body, `<section id="getPet">
+  <h3><span class="method">GET</span> <code>/pets/{id}</code></h3>
+  <p><strong>Find a pet</strong></p>
+  <p>Tags: pets</p>`)
+	assert.Contains(t, body, `<tr><td><code>id</code> (required)</td><td>path</td><td>integer (int64)</td><td></td></tr>`)
+	assert.Contains(t, body, `<tr><td>200</td><td>Pet</td><td>OK</td></tr>`)
+	assert.Contains(t, body, `<section id="delete-pets-id">
+  <h3 class="deprecated"><span class="method">DELETE</span>`)
+	assert.Contains(t, body, `<tr><td><code>name</code> (required)</td><td>string</td><td></td></tr>`)
+	assert.Contains(t, body, `<tr><td><code>tags</code></td><td>[]string</td><td></td></tr>`)
+	assert.Contains(t, body, `<a href="doc.json">API definition</a>`)
+
+	openAPI3 := `openapi: 3.0.3
+info:
+  title: Orders
+  version: "2.0"
+paths:
+  /orders:
+    post:
+      parameters:
+        - name: dry_run
+          in: query
+          schema:
+            type: boolean
+      responses:
+        "201":
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Order"
+components:
+  schemas:
+    Order:
+      description: An order.
+      properties:
+        id:
+          type: string
+`
+
+	h = Handler(StaticRender(true), SpecProvider(func(context.Context) ([]byte, string, error) {
+		return []byte(openAPI3), "application/x-yaml", nil
+	}))
+
+	body = performRequest(http.MethodGet, "/index.html", h).Body.String()
+	assert.Contains(t, body, "<h1>Orders <small>2.0</small></h1>")
+	assert.Contains(t, body, `<tr><td><code>dry_run</code></td><td>query</td><td>boolean</td><td></td></tr>`)
+	assert.Contains(t, body, `<tr><td>201</td><td>Order</td><td>Created</td></tr>`)
+	assert.Contains(t, body, `<section id="schema-Order">
+  <h3>Order</h3>
+  <p>An order.</p>`)
+
+	var buf bytes.Buffer
+	h = Handler(StaticRender(true), Logger(log.New(&buf, "", 0)), SpecProvider(func(context.Context) ([]byte, string, error) {
+		return []byte("- not an object"), "application/x-yaml", nil
+	}))
+
+	assert.Equal(t, http.StatusInternalServerError, performRequest(http.MethodGet, "/index.html", h).Code)
+	assert.Contains(t, buf.String(), "httpSwagger: rendering index page: httpSwagger: decoding API definition")
+
+	swag.Register("TestStaticRender", &mockedSwag{})
+	body = performRequest(http.MethodGet, "/index.html", Handler(StaticRender(true), InstanceName("TestStaticRender"))).Body.String()
+	assert.Contains(t, body, "<title>Swagger Example API</title>")
+	assert.NotContains(t, body, "SwaggerUIBundle")
+}
+
+func TestTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("custom").Parse(`<title>{{.Title}}</title><script src="{{.AssetsPrefix}}swagger-ui-bundle.js"></script><a href="{{.DocURL}}">spec</a>`))
+
+	cfg := Config{}
+	configFunc := Template(tmpl)
+	configFunc(&cfg)
+	assert.Equal(t, tmpl, cfg.Template)
+
+	w := performRequest(http.MethodGet, "/index.html", Handler(Template(tmpl), Title("Branded")))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `<title>Branded</title><script src="./swagger-ui-bundle.js"></script><a href="doc.json">spec</a>`, w.Body.String())
+
+	var buf bytes.Buffer
+	tmpl = template.Must(template.New("data").Parse(`{{.Config.InstanceName}} {{.DomID}} {{.Nonce}}`))
+	assert.NoError(t, tmpl.Execute(&buf, TemplateData{Config: newConfig(DomID("#docs")), DomID: "docs", Nonce: "abc"}))
+	assert.Equal(t, "swagger docs abc", buf.String())
+}
+
+func TestRenderer(t *testing.T) {
+	expected := "redoc"
+	cfg := Config{}
+	configFunc := Renderer(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.Renderer)
+
+	assert.Equal(t, "swagger", newConfig(Renderer("")).Renderer)
+
+	w := performRequest(http.MethodGet, "/index.html", Handler(Renderer("redoc"), Title("API Reference v2")))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "<title>API Reference v2</title>")
+	assert.Contains(t, w.Body.String(), `<script src="https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"> </script>`)
+	assert.Contains(t, w.Body.String(), `Redoc.init("doc.json", {}, document.getElementById("swagger-ui"))`)
+	assert.NotContains(t, w.Body.String(), "SwaggerUIBundle")
+
+	_, err := HandlerWithError(Renderer("rapidoc"), StrictValidation(true))
+	assert.Error(t, err)
+}
+
+func TestTheme(t *testing.T) {
+	expected := "dark"
+	cfg := Config{}
+	configFunc := Theme(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.Theme)
+
+	assert.Equal(t, "light", newConfig().Theme)
+
+	page, err := RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), string(darkThemeCSS))
+
+	page, err = RenderHTML(Theme("dark"), CustomCSS(".swagger-ui { color: red; }"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "<style>\n    "+string(darkThemeCSS)+"\n  </style>")
+	// the CustomCSS overrides the theme
+	assert.Less(t, strings.Index(string(page), string(darkThemeCSS)), strings.Index(string(page), ".swagger-ui { color: red; }"))
+
+	page, err = RenderHTML(Theme("auto"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `<style media="(prefers-color-scheme: dark)">`+"\n    "+string(darkThemeCSS)+"\n  </style>")
+
+	var buf bytes.Buffer
+	w := performRequest(http.MethodGet, "/index.html", Handler(Theme("sepia"), Logger(log.New(&buf, "", 0))))
+	assert.NotContains(t, w.Body.String(), string(darkThemeCSS))
+	assert.Contains(t, buf.String(), `httpSwagger: invalid theme "sepia", using "light"`)
+
+	_, err = HandlerWithError(Theme("sepia"), StrictValidation(true))
+	assert.Error(t, err)
+}
+
+func TestRedocBundleURL(t *testing.T) {
+	expected := "./redoc.standalone.js"
+	cfg := Config{}
+	configFunc := RedocBundleURL(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.RedocBundleURL)
+
+	page, err := RenderHTML(Renderer("redoc"), RedocBundleURL(expected))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `<script src="./redoc.standalone.js"> </script>`)
+
+	page, err = RenderHTML(Renderer("redoc"), AssetIntegrity(map[string]string{"redoc.standalone.js": "sha384-redoc"}))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `src="https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js" integrity="sha384-redoc" crossorigin="anonymous"> </script>`)
+
+	// the offline page does not load the bundle from the Redoc CDN
+	page, err = RenderHTML(OfflineMode(), Renderer("redoc"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), ` src="./redoc.standalone.js"> </script>`)
+	assert.NotContains(t, string(page), "cdn.redoc.ly")
+}
+
+func TestQueryConfigEnabled(t *testing.T) {
 	expected := true
 	cfg := Config{}
-	configFunc := DeepLinking(expected)
+	configFunc := QueryConfigEnabled(expected)
 	configFunc(&cfg)
-	assert.Equal(t, expected, cfg.DeepLinking)
+	assert.Equal(t, expected, cfg.QueryConfigEnabled)
+
+	page, err := RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), "queryConfigEnabled")
+
+	page, err = RenderHTML(QueryConfigEnabled(true))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "queryConfigEnabled: true,")
 }
 
-func TestDocExpansion(t *testing.T) {
-	expected := "https://github.com/swaggo/docs"
+func TestDisplayRequestDuration(t *testing.T) {
+	expected := true
 	cfg := Config{}
-	configFunc := DocExpansion(expected)
+	configFunc := DisplayRequestDuration(expected)
 	configFunc(&cfg)
-	assert.Equal(t, expected, cfg.DocExpansion)
+	assert.Equal(t, expected, cfg.DisplayRequestDuration)
+
+	page, err := RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), "displayRequestDuration")
+
+	w := performRequest(http.MethodGet, "/index.html", Handler(DisplayRequestDuration(true)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "displayRequestDuration: true,")
 }
 
-func TestDomID(t *testing.T) {
-	expected := "#swagger-ui"
+func TestShowExtensions(t *testing.T) {
+	expected := true
 	cfg := Config{}
-	configFunc := DomID(expected)
+	configFunc := ShowExtensions(expected)
 	configFunc(&cfg)
-	assert.Equal(t, expected, cfg.DomID)
+	assert.Equal(t, expected, cfg.ShowExtensions)
+
+	page, err := RenderHTML()
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "\n    showExtensions: false,\n")
+
+	page, err = RenderHTML(ShowExtensions(false))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "\n    showExtensions: false,\n")
+
+	w := performRequest(http.MethodGet, "/index.html", Handler(ShowExtensions(true)))
+	assert.Contains(t, w.Body.String(), "\n    showExtensions: true,\n")
+
+	page, err = RenderHTML(ShowExtensions(true), MinifyConfig(true))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "showExtensions:true,")
 }
 
-func TestInstanceName(t *testing.T) {
-	var cfg Config
+func TestShowCommonExtensions(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := ShowCommonExtensions(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.ShowCommonExtensions)
 
-	assert.Equal(t, "", cfg.InstanceName)
+	page, err := RenderHTML()
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "\n    showCommonExtensions: false,\n")
 
-	expected := swag.Name
-	InstanceName(expected)(&cfg)
-	assert.Equal(t, expected, cfg.InstanceName)
+	page, err = RenderHTML(ShowCommonExtensions(false))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "\n    showCommonExtensions: false,\n")
 
-	expected = "custom_name"
-	InstanceName(expected)(&cfg)
-	assert.Equal(t, expected, cfg.InstanceName)
+	w := performRequest(http.MethodGet, "/index.html", Handler(ShowCommonExtensions(true)))
+	assert.Contains(t, w.Body.String(), "\n    showCommonExtensions: true,\n")
 
-	newCfg := newConfig(InstanceName(""))
-	assert.Equal(t, swag.Name, newCfg.InstanceName)
+	page, err = RenderHTML(ShowCommonExtensions(true), MinifyConfig(true))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "showCommonExtensions:true,")
 }
 
-func TestPersistAuthorization(t *testing.T) {
+func TestRequestSnippetsEnabled(t *testing.T) {
 	expected := true
 	cfg := Config{}
-	configFunc := PersistAuthorization(expected)
+	configFunc := RequestSnippetsEnabled(expected)
 	configFunc(&cfg)
-	assert.Equal(t, expected, cfg.PersistAuthorization)
+	assert.Equal(t, expected, cfg.RequestSnippetsEnabled)
+}
+
+func TestRequestSnippets(t *testing.T) {
+	cfg := Config{}
+	configFunc := RequestSnippets(func(c *RequestSnippetsConfig) {
+		c.Languages = []string{"curl_bash"}
+	})
+	configFunc(&cfg)
+	assert.Equal(t, &RequestSnippetsConfig{DefaultExpanded: true, Languages: []string{"curl_bash"}}, cfg.RequestSnippets)
+
+	page, err := RenderHTML(RequestSnippetsEnabled(true), RequestSnippets(func(c *RequestSnippetsConfig) {
+		c.Generators = map[string]RequestSnippetGenerator{
+			"curl_bash": {Title: "cURL (bash)", Syntax: "bash"},
+		}
+		c.DefaultExpanded = false
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), "requestSnippetsEnabled: true,\n")
+	assert.Contains(t, string(page), `requestSnippets: {"generators":{"curl_bash":{"title":"cURL (bash)","syntax":"bash"}},"defaultExpanded":false},`)
+}
+
+func TestWithCredentials(t *testing.T) {
+	expected := true
+	cfg := Config{}
+	configFunc := WithCredentials(expected)
+	configFunc(&cfg)
+	assert.Equal(t, expected, cfg.WithCredentials)
+}
+
+func TestSupportedSubmitMethods(t *testing.T) {
+	cfg := Config{}
+	configFunc := SupportedSubmitMethods("GET", "post")
+	configFunc(&cfg)
+	assert.Equal(t, []string{"get", "post"}, cfg.SupportedSubmitMethods)
+
+	SupportedSubmitMethods()(&cfg)
+	assert.NotNil(t, cfg.SupportedSubmitMethods)
+	assert.Empty(t, cfg.SupportedSubmitMethods)
+
+	page, err := RenderHTML(SupportedSubmitMethods("get", "post"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `supportedSubmitMethods: ["get","post"],`)
+
+	page, err = RenderHTML(SupportedSubmitMethods())
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `supportedSubmitMethods: [],`)
+
+	page, err = RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), `supportedSubmitMethods`)
+}
+
+func TestSubmitMethodRules(t *testing.T) {
+	rules := []SubmitMethodRule{
+		{Tag: "staging", Method: "POST", Enabled: true},
+		{Path: "/admin/*", Enabled: false},
+	}
+
+	cfg := Config{}
+	configFunc := SubmitMethodRules(rules...)
+	configFunc(&cfg)
+	assert.Equal(t, rules, cfg.SubmitMethodRules)
+
+	page, err := RenderHTML(SupportedSubmitMethods("get"), SubmitMethodRules(rules...))
+	assert.NoError(t, err)
+	assert.Contains(t, string(page), `const rules = [{"method":"POST","tag":"staging","enabled":true},{"path":"/admin/*","enabled":false}]`)
+	assert.Contains(t, string(page), `operation: operation.set("allowTryItOut", rule.enabled)`)
+	assert.Contains(t, string(page), `supportedSubmitMethods: ["get"],`)
+
+	page, err = RenderHTML()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(page), "allowTryItOut")
+}
+
+func TestAllowedOrigins(t *testing.T) {
+	cfg := Config{}
+	configFunc := AllowedOrigins("https://editor.swagger.io")
+	configFunc(&cfg)
+	assert.Equal(t, []string{"https://editor.swagger.io"}, cfg.AllowedOrigins)
+
+	swag.Register("cors", &mockedSwag{})
+
+	h := Handler(InstanceName("cors"), AllowedOrigins("https://editor.swagger.io"))
+
+	r := httptest.NewRequest(http.MethodOptions, "/doc.json", nil)
+	r.Header.Set("Origin", "https://editor.swagger.io")
+	r.Header.Set("Access-Control-Request-Headers", "accept")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://editor.swagger.io", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, HEAD, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "accept", w.Header().Get("Access-Control-Allow-Headers"))
+
+	r = httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+	r.Header.Set("Origin", "https://editor.swagger.io")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://editor.swagger.io", w.Header().Get("Access-Control-Allow-Origin"))
+
+	r = httptest.NewRequest(http.MethodGet, "/doc.json", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, performRequest(http.MethodOptions, "/index.html", h).Code)
+
+	w = performRequest(http.MethodOptions, "/doc.json", Handler(InstanceName("cors")))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestBasicAuth(t *testing.T) {
+	cfg := Config{}
+	configFunc := BasicAuth("admin", "secret")
+	configFunc(&cfg)
+	assert.Equal(t, &BasicAuthConfig{Username: "admin", Password: "secret"}, cfg.BasicAuth)
+
+	h := Handler(BasicAuth("admin", "secret"))
+
+	for _, target := range []string{"/index.html", "/doc.json", "/swagger-ui.css"} {
+		w := performRequest(http.MethodGet, target, h)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, target)
+		assert.Equal(t, `Basic realm="Swagger UI", charset="UTF-8"`, w.Header().Get("WWW-Authenticate"), target)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/index.html", nil)
+	r.SetBasicAuth("admin", "wrong")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	r = httptest.NewRequest(http.MethodGet, "/index.html", nil)
+	r.SetBasicAuth("admin", "secret")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthorize(t *testing.T) {
+	cfg := Config{}
+	configFunc := Authorize(func(*http.Request) error { return ErrUnauthorized })
+	configFunc(&cfg)
+	assert.Equal(t, ErrUnauthorized, cfg.Authorize(nil))
+
+	assert.True(t, errors.Is(Unauthorized(`Bearer realm="api"`), ErrUnauthorized))
+
+	authorize := Authorize(func(r *http.Request) error {
+		switch r.Header.Get("X-Session") {
+		case "":
+			return ErrUnauthorized
+		case "valid":
+			return nil
+		case "bearer":
+			return fmt.Errorf("no token: %w", Unauthorized(`Bearer realm="api"`))
+		default:
+			return errors.New("expired session")
+		}
+	})
+	h := Handler(authorize)
+
+	// without a challenge to send, ErrUnauthorized is forbidden
+	w := performRequest(http.MethodGet, "/index.html", h)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("WWW-Authenticate"))
+
+	for session, status := range map[string]int{"valid": http.StatusOK, "expired": http.StatusForbidden, "bearer": http.StatusUnauthorized} {
+		r := httptest.NewRequest(http.MethodGet, "/swagger-ui.css", nil)
+		r.Header.Set("X-Session", session)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assert.Equal(t, status, w.Code, session)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/index.html", nil)
+	r.Header.Set("X-Session", "bearer")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	assert.Equal(t, `Bearer realm="api"`, w.Header().Get("WWW-Authenticate"))
+
+	// the BasicAuth challenge is sent along with ErrUnauthorized
+	r = httptest.NewRequest(http.MethodGet, "/index.html", nil)
+	r.SetBasicAuth("admin", "secret")
+	w = httptest.NewRecorder()
+	Handler(BasicAuth("admin", "secret"), authorize).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, `Basic realm="Swagger UI", charset="UTF-8"`, w.Header().Get("WWW-Authenticate"))
 }
 
 func TestConfigURL(t *testing.T) {
@@ -209,6 +2759,34 @@ func TestConfigURL(t *testing.T) {
 				"urls": `["https://example.org/doc1.json","https://example.org/doc1.json"],`,
 			}),
 		},
+		{
+			desc: "configure RequestInterceptor",
+			exp: &Config{
+				RequestInterceptor: `(req) => req`,
+			},
+			cfgfn: RequestInterceptor(`(req) => req`),
+		},
+		{
+			desc: "configure ResponseInterceptor",
+			exp: &Config{
+				ResponseInterceptor: `(res) => res`,
+			},
+			cfgfn: ResponseInterceptor(`(res) => res`),
+		},
+		{
+			desc: "configure ModelPropertyMacro",
+			exp: &Config{
+				ModelPropertyMacro: `(property) => property.example`,
+			},
+			cfgfn: ModelPropertyMacro(`(property) => property.example`),
+		},
+		{
+			desc: "configure ParameterMacro",
+			exp: &Config{
+				ParameterMacro: `(operation, parameter) => parameter.example`,
+			},
+			cfgfn: ParameterMacro(`(operation, parameter) => parameter.example`),
+		},
 		{
 			desc: "configure BeforeScript",
 			exp: &Config{
@@ -247,7 +2825,14 @@ func TestUIConfigOptions(t *testing.T) {
 	type fixture struct {
 		desc string
 		cfg  *Config
-		exp  string
+		// the id of the element Swagger UI is mounted in, swagger-ui when empty
+		domID string
+		exp   string
+	}
+
+	// html/template escapes the + of the base64 digests in attributes
+	integrity := func(name string) string {
+		return strings.ReplaceAll(assets[name].Integrity(), "+", "&#43;")
 	}
 
 	hdr := `
@@ -256,7 +2841,7 @@ func TestUIConfigOptions(t *testing.T) {
 <head>
   <meta charset="UTF-8">
   <title>Swagger UI</title>
-  <link rel="stylesheet" type="text/css" href="./swagger-ui.css" >
+  <link rel="stylesheet" type="text/css" href="./swagger-ui.css" integrity="` + integrity("swagger-ui.css") + `" >
   <link rel="icon" type="image/png" href="./favicon-32x32.png" sizes="32x32" />
   <link rel="icon" type="image/png" href="./favicon-16x16.png" sizes="16x16" />
   <style>
@@ -316,8 +2901,8 @@ func TestUIConfigOptions(t *testing.T) {
 
 <div id="swagger-ui"></div>
 
-<script src="./swagger-ui-bundle.js"> </script>
-<script src="./swagger-ui-standalone-preset.js"> </script>
+<script src="./swagger-ui-bundle.js" integrity="` + integrity("swagger-ui-bundle.js") + `"> </script>
+<script src="./swagger-ui-standalone-preset.js" integrity="` + integrity("swagger-ui-standalone-preset.js") + `"> </script>
 <script>
 `
 	ftr := `
@@ -336,6 +2921,12 @@ func TestUIConfigOptions(t *testing.T) {
 				DocExpansion:         "list",
 				DomID:                "swagger-ui",
 				PersistAuthorization: false,
+				Layout:               "BaseLayout",
+				ShowTopBar:           true,
+				ShowModels:           true,
+				ShowDownloadButton:   true,
+				ValidatorURL:         new(string),
+				ShowMutatedRequest:   true,
 			},
 			exp: `window.onload = function() {
   
@@ -345,7 +2936,10 @@ func TestUIConfigOptions(t *testing.T) {
     docExpansion: "list",
     dom_id: "#swagger-ui",
     persistAuthorization:  false ,
+    showExtensions: false,
+    showCommonExtensions: false,
     validatorUrl: null,
+    oauth2RedirectUrl: window.location.origin + window.location.pathname.replace(/[^/]*$/, "oauth2-redirect.html"),
     presets: [
       SwaggerUIBundle.presets.apis,
       SwaggerUIStandalonePreset
@@ -353,20 +2947,40 @@ func TestUIConfigOptions(t *testing.T) {
     plugins: [
       SwaggerUIBundle.plugins.DownloadUrl
     ],
-    layout: "StandaloneLayout"
+    layout: "BaseLayout"
   })
 
   window.ui = ui
 }`,
 		},
 		{
-			desc: "script configuration",
+			desc:  "script configuration",
+			domID: "swagger-ui-id",
 			cfg: &Config{
 				URL:                  "swagger.json",
 				DeepLinking:          false,
 				PersistAuthorization: true,
+				Filter:               true,
+				TryItOutEnabled:      true,
+				WithCredentials:      true,
+				MaxDisplayedTags:     10,
+				SyntaxHighlight:      &SyntaxHighlightConfig{Activate: true, Theme: "monokai"},
+				RequestInterceptor:   `(req) => { req.headers["X-Trace"] = "1"; return req; }`,
+				ResponseInterceptor:  `(res) => res`,
+				ModelPropertyMacro:   `(property) => property.example`,
+				ParameterMacro:       `(operation, parameter) => parameter.example`,
 				DocExpansion:         "none",
 				DomID:                "swagger-ui-id",
+				Layout:               "StandaloneLayout",
+				ShowTopBar:           true,
+				ShowModels:           true,
+				ShowDownloadButton:   true,
+				ValidatorURL:         new(string),
+				ShowMutatedRequest:   true,
+				URLs: []URLsConfig{
+					{URL: "swagger.json", Name: "v1"},
+					{URL: "swagger-v2.json", Name: "v2"},
+				},
 				BeforeScript: `const SomePlugin = (system) => ({
     // Some plugin
   });
@@ -393,11 +3007,24 @@ func TestUIConfigOptions(t *testing.T) {
   
   const ui = SwaggerUIBundle({
     url: "swagger.json",
+    urls: [{"url":"swagger.json","name":"v1"},{"url":"swagger-v2.json","name":"v2"}],
     deepLinking:  false ,
     docExpansion: "none",
     dom_id: "#swagger-ui-id",
     persistAuthorization:  true ,
+    filter: true,
+    maxDisplayedTags:  10 ,
+    syntaxHighlight: {"activate":true,"theme":"monokai"},
+    requestInterceptor: (req) => { req.headers["X-Trace"] = "1"; return req; },
+    responseInterceptor: (res) => res,
+    tryItOutEnabled: true,
+    showExtensions: false,
+    showCommonExtensions: false,
+    withCredentials: true,
+    modelPropertyMacro: (property) => property.example,
+    parameterMacro: (operation, parameter) => parameter.example,
     validatorUrl: null,
+    oauth2RedirectUrl: window.location.origin + window.location.pathname.replace(/[^/]*$/, "oauth2-redirect.html"),
     presets: [
       SwaggerUIBundle.presets.apis,
       SwaggerUIStandalonePreset
@@ -418,24 +3045,74 @@ func TestUIConfigOptions(t *testing.T) {
     // Do something
   };
   someOtherCode();
+}`,
+		},
+		{
+			desc: "read only configuration",
+			cfg: &Config{
+				URL:                "doc.json",
+				DeepLinking:        true,
+				DocExpansion:       "list",
+				DomID:              "swagger-ui",
+				ReadOnly:           true,
+				Layout:             "BaseLayout",
+				ShowTopBar:         true,
+				ShowModels:         true,
+				ShowDownloadButton: true,
+				ValidatorURL:       new(string),
+				ShowMutatedRequest: true,
+			},
+			exp: `window.onload = function() {
+  
+  const ui = SwaggerUIBundle({
+    url: "doc.json",
+    deepLinking:  true ,
+    docExpansion: "list",
+    dom_id: "#swagger-ui",
+    persistAuthorization:  false ,
+    showExtensions: false,
+    showCommonExtensions: false,
+    supportedSubmitMethods: [],
+    validatorUrl: null,
+    oauth2RedirectUrl: window.location.origin + window.location.pathname.replace(/[^/]*$/, "oauth2-redirect.html"),
+    presets: [
+      SwaggerUIBundle.presets.apis,
+      SwaggerUIStandalonePreset
+    ],
+    plugins: [
+      SwaggerUIBundle.plugins.DownloadUrl,
+      () => ({
+        wrapComponents: {
+          authorizeBtn: () => () => null,
+          authorizeOperationBtn: () => () => null,
+          TryItOutButton: () => () => null,
+          execute: () => () => null
+        }
+      })
+    ],
+    layout: "BaseLayout"
+  })
+
+  window.ui = ui
 }`,
 		},
 	}
 
 	for _, fix := range fixtures {
 		t.Run(fix.desc, func(t *testing.T) {
-			tmpl := template.New("swagger_index.html")
-			index, err := tmpl.Parse(indexTempl)
+			page, err := RenderHTML(func(c *Config) { *c = *fix.cfg })
 			if err != nil {
 				t.Fatal(err)
 			}
 
-			buf := bytes.NewBuffer(nil)
-			if err := index.Execute(buf, fix.cfg); err != nil {
-				t.Fatal(err)
+			buf := bytes.NewBuffer(page)
+
+			domID := fix.domID
+			if domID == "" {
+				domID = "swagger-ui"
 			}
 
-			exp := hdr + fix.exp + ftr
+			exp := strings.Replace(hdr, `<div id="swagger-ui">`, `<div id="`+domID+`">`, 1) + fix.exp + ftr
 
 			// Compare line by line
 			explns := strings.Split(exp, "\n")
@@ -0,0 +1,35 @@
+package httpSwagger
+
+import "html/template"
+
+// darkThemeCSS is the overlay of the bundled swagger-ui.css applied by the dark Theme.
+const darkThemeCSS template.CSS = `body { background: #1b1b1f; }
+    .swagger-ui { color: #d8dee9; }
+    .swagger-ui .info .title, .swagger-ui .info p, .swagger-ui .info li, .swagger-ui .info table,
+    .swagger-ui .opblock-tag, .swagger-ui .opblock .opblock-summary-description,
+    .swagger-ui .opblock .opblock-summary-operation-id, .swagger-ui .opblock .opblock-summary-path,
+    .swagger-ui .opblock-description-wrapper p, .swagger-ui .opblock-external-docs-wrapper p,
+    .swagger-ui .opblock-title_normal p, .swagger-ui .opblock .opblock-section-header h4,
+    .swagger-ui .parameter__name, .swagger-ui .parameter__type, .swagger-ui .parameter__in,
+    .swagger-ui .response-col_status, .swagger-ui .response-col_links, .swagger-ui .responses-inner h4,
+    .swagger-ui .responses-inner h5, .swagger-ui table thead tr th, .swagger-ui table thead tr td,
+    .swagger-ui .tab li, .swagger-ui label, .swagger-ui .model, .swagger-ui .model-title,
+    .swagger-ui section.models h4, .swagger-ui .servers-title, .swagger-ui .scheme-container .schemes > label,
+    .swagger-ui .markdown p, .swagger-ui .markdown li, .swagger-ui .renderedMarkdown p,
+    .swagger-ui .btn, .swagger-ui .dialog-ux .modal-ux-header h3, .swagger-ui .dialog-ux .modal-ux-content p,
+    .swagger-ui .dialog-ux .modal-ux-content h4 { color: #d8dee9; }
+    .swagger-ui a, .swagger-ui .info a, .swagger-ui .info .base-url { color: #81a1c1; }
+    .swagger-ui .scheme-container, .swagger-ui .opblock .opblock-section-header,
+    .swagger-ui .dialog-ux .modal-ux, .swagger-ui .model-box, .swagger-ui section.models .model-container,
+    .swagger-ui .topbar { background: #25262b; box-shadow: none; }
+    .swagger-ui .opblock-tag, .swagger-ui section.models, .swagger-ui section.models.is-open h4,
+    .swagger-ui .dialog-ux .modal-ux-header, .swagger-ui table thead tr th,
+    .swagger-ui table thead tr td { border-color: #3b3d45; }
+    .swagger-ui input[type=text], .swagger-ui input[type=password], .swagger-ui input[type=search],
+    .swagger-ui input[type=email], .swagger-ui input[type=file], .swagger-ui textarea,
+    .swagger-ui select { background: #2e3038; color: #d8dee9; border-color: #4c4f5a; }
+    .swagger-ui .model-toggle:after, .swagger-ui .expand-operation svg, .swagger-ui .arrow,
+    .swagger-ui .authorization__btn svg, .swagger-ui .opblock-control-arrow svg,
+    .swagger-ui .models-control svg { filter: invert(0.85); }
+    .swagger-ui .prop-type { color: #b48ead; }
+    .swagger-ui .prop-format { color: #8f96a3; }`